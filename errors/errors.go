@@ -85,6 +85,15 @@ func (e *Error) ErrorWithFrame() string {
 	return fmt.Sprintf("%s %s", e.frame, e.err.Error())
 }
 
+// Frame returns the file and line number where the error was created.
+// For example: "/path/to/file.go:123"
+func (e *Error) Frame() string {
+	if e == nil {
+		return ""
+	}
+	return e.frame
+}
+
 func (e *Error) Unwrap() error {
 	if e == nil {
 		return nil
@@ -22,6 +22,9 @@ func TestNewIncludesStack(t *testing.T) {
 	if !pattern.MatchString(e.ErrorWithFrame()) {
 		t.Fatalf("expected stack trace, got %s", e.ErrorWithFrame())
 	}
+	if !regexp.MustCompile(`errors_test.go:\d+$`).MatchString(e.Frame()) {
+		t.Fatalf("expected frame, got %s", e.Frame())
+	}
 }
 
 func TestWrap(t *testing.T) {
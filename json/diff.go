@@ -0,0 +1,146 @@
+package json
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ChangeType is the kind of a Change.
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// Change is a difference between two JSON documents, see Diff.
+type Change struct {
+	Type ChangeType
+	// Path is the human-readable path of the value, e.g.
+	// spec.containers[0].image or labels["app.kubernetes.io/name"], empty for
+	// the root.
+	Path string
+	// Pointer is the JSON Pointer of the value, e.g. /spec/containers/0/image.
+	Pointer string
+	// From is the original value, nil for added values. To is the modified
+	// value, nil for removed values. Objects and arrays are
+	// map[string]interface{} and []interface{}, numbers are Number.
+	From, To interface{}
+}
+
+// String formats the change for humans, e.g.
+// `spec.replicas: 2 -> 3`, `+ labels.team: "core"` or `- debug: true`.
+func (c Change) String() string {
+	path := c.Path
+	if path == "" {
+		path = "(root)"
+	}
+	switch c.Type {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s: %s", path, formatValue(c.To))
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s: %s", path, formatValue(c.From))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", path, formatValue(c.From), formatValue(c.To))
+	}
+}
+
+func formatValue(v interface{}) string {
+	data, err := Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// Diff returns the differences between the JSON documents original and
+// modified, e.g. to report configuration changes:
+//
+//	changes, err := json.Diff(before, after)
+//	for _, change := range changes {
+//		log.Println(change) // ~ spec.replicas: 2 -> 3
+//	}
+//
+// Object members are compared in sorted order and arrays element by
+// element: added and removed elements are at the end, removed ones are
+// reported last first. Numbers are compared by value. The changes are those
+// of CreatePatch, in the same order.
+func Diff(original, modified []byte) ([]Change, error) {
+	a, err := parseDocument(original)
+	if err != nil {
+		return nil, fmt.Errorf("json: original: %w", err)
+	}
+	b, err := parseDocument(modified)
+	if err != nil {
+		return nil, fmt.Errorf("json: modified: %w", err)
+	}
+	return diff(a, b), nil
+}
+
+// DiffValues returns the differences between original and modified, as
+// encoded by the marshal engine, see Diff.
+func DiffValues(original, modified interface{}) ([]Change, error) {
+	a, err := toDocument(original)
+	if err != nil {
+		return nil, err
+	}
+	b, err := toDocument(modified)
+	if err != nil {
+		return nil, err
+	}
+	return diff(a, b), nil
+}
+
+func diff(original, modified interface{}) []Change {
+	changes := []Change{}
+	walkChanges(original, modified, pointer{}, func(change Change, path pointer) {
+		doc := modified
+		if change.Type == ChangeRemoved {
+			doc = original
+		}
+		change.Path = readablePath(doc, path)
+		change.Pointer = path.String()
+		changes = append(changes, change)
+	})
+	return changes
+}
+
+// walkChanges calls fn with the changes turning a into b, at path.
+func walkChanges(a, b interface{}, path pointer, fn func(change Change, path pointer)) {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		if b, ok := b.(map[string]interface{}); ok {
+			for _, key := range sortedKeys(a) {
+				if value, ok := b[key]; ok {
+					walkChanges(a[key], value, path.append(key), fn)
+				} else {
+					fn(Change{Type: ChangeRemoved, From: a[key]}, path.append(key))
+				}
+			}
+			for _, key := range sortedKeys(b) {
+				if _, ok := a[key]; !ok {
+					fn(Change{Type: ChangeAdded, To: b[key]}, path.append(key))
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if b, ok := b.([]interface{}); ok {
+			for i := 0; i < min(len(a), len(b)); i++ {
+				walkChanges(a[i], b[i], path.append(strconv.Itoa(i)), fn)
+			}
+			// Removed from the end, so that the indexes of a patch stay valid.
+			for i := len(a) - 1; i >= len(b); i-- {
+				fn(Change{Type: ChangeRemoved, From: a[i]}, path.append(strconv.Itoa(i)))
+			}
+			for i := len(a); i < len(b); i++ {
+				fn(Change{Type: ChangeAdded, To: b[i]}, path.append(strconv.Itoa(i)))
+			}
+			return
+		}
+	}
+	if !equalDocuments(a, b) {
+		fn(Change{Type: ChangeModified, From: a, To: b}, path)
+	}
+}
@@ -0,0 +1,99 @@
+package json
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	original := `{
+		"spec": {"replicas": 2, "containers": [{"image": "api:1.0", "ports": [80]}]},
+		"labels": {"app.kubernetes.io/name": "api", "team": "core"},
+		"debug": true,
+		"weight": 1.0
+	}`
+	modified := `{
+		"spec": {"replicas": 3, "containers": [{"image": "api:1.1", "ports": [80, 443]}]},
+		"labels": {"app.kubernetes.io/name": "api-v2"},
+		"tags": ["a"],
+		"weight": 1
+	}`
+	changes, err := Diff([]byte(original), []byte(modified))
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	var got []string
+	for _, change := range changes {
+		got = append(got, change.String()+" @ "+change.Pointer)
+	}
+	want := []string{
+		`- debug: true @ /debug`,
+		`~ labels["app.kubernetes.io/name"]: "api" -> "api-v2" @ /labels/app.kubernetes.io~1name`,
+		`- labels.team: "core" @ /labels/team`,
+		`~ spec.containers[0].image: "api:1.0" -> "api:1.1" @ /spec/containers/0/image`,
+		`+ spec.containers[0].ports[1]: 443 @ /spec/containers/0/ports/1`,
+		`~ spec.replicas: 2 -> 3 @ /spec/replicas`,
+		`+ tags: ["a"] @ /tags`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Diff() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if changes[0].Type != ChangeRemoved || changes[0].From != true || changes[0].To != nil {
+		t.Errorf("Diff()[0] = %+v, want debug removed", changes[0])
+	}
+	if changes[5].From != Number("2") || changes[5].To != Number("3") {
+		t.Errorf("Diff()[5] = %+v, want numbers 2 and 3", changes[5])
+	}
+}
+
+func TestDiff_Paths(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		modified string
+		want     string
+	}{
+		{name: "root", original: `1`, modified: `2`, want: `~ (root): 1 -> 2`},
+		{name: "root array", original: `[{"a":1}]`, modified: `[{"a":2}]`, want: `~ [0].a: 1 -> 2`},
+		{name: "empty key", original: `{"":1}`, modified: `{"":2}`, want: `~ [""]: 1 -> 2`},
+		{name: "removed elements last first", original: `[1,2,3]`, modified: `[1]`, want: "- [2]: 3\n- [1]: 2"},
+		{name: "added object", original: `{}`, modified: `{"a b":{"c":1}}`, want: `+ ["a b"]: {"c":1}`},
+		{name: "equal", original: `{"a":[1,{"b":null}]}`, modified: `{"a":[1,{"b":null}]}`, want: ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := Diff([]byte(tt.original), []byte(tt.modified))
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			var got []string
+			for _, change := range changes {
+				got = append(got, change.String())
+			}
+			if strings.Join(got, "\n") != tt.want {
+				t.Errorf("Diff() = %q, want %q", strings.Join(got, "\n"), tt.want)
+			}
+		})
+	}
+
+	if _, err := Diff([]byte(`{}`), []byte(`{} {}`)); err == nil {
+		t.Error("Diff() should reject trailing data")
+	}
+}
+
+func TestDiffValues(t *testing.T) {
+	original := patchTarget{Name: "api", Replicas: 2, Ports: []int{80}}
+	modified := patchTarget{Name: "web", Replicas: 2, Ports: []int{80}}
+	changes, err := DiffValues(original, modified)
+	if err != nil {
+		t.Fatalf("DiffValues() error = %v", err)
+	}
+	if len(changes) != 1 || changes[0].String() != `~ name: "api" -> "web"` {
+		t.Errorf("DiffValues() = %v", changes)
+	}
+	if _, err := DiffValues(make(chan int), 1); err == nil {
+		t.Error("DiffValues() should fail for values that cannot be encoded")
+	}
+}
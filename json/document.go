@@ -0,0 +1,112 @@
+package json
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// parseDocument decodes a JSON document with the unmarshal engine into
+// map[string]interface{}, []interface{}, string, Number, bool and nil
+// values. Numbers are kept as written, so they are not rounded when the
+// document is written back.
+func parseDocument(data []byte) (interface{}, error) {
+	dec := UnmarshalEngine().NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if token, err := dec.Token(); err != io.EOF {
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("json: invalid data after top-level value: %v", token)
+	}
+	return doc, nil
+}
+
+// toDocument converts v to a document, as encoded by the marshal engine.
+func toDocument(v interface{}) (interface{}, error) {
+	data, err := Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return parseDocument(data)
+}
+
+// fromDocument converts doc to a T, as decoded by the unmarshal engine.
+func fromDocument[T any](doc interface{}) (T, error) {
+	var v T
+	data, err := Marshal(doc)
+	if err != nil {
+		return v, err
+	}
+	err = Unmarshal(data, &v)
+	return v, err
+}
+
+// equalDocuments reports whether a and b are the same JSON value. Numbers
+// are compared by value, e.g. 1 and 1.0 are equal.
+func equalDocuments(a, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, value := range a {
+			other, ok := b[key]
+			if !ok || !equalDocuments(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equalDocuments(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case Number:
+		b, ok := b.(Number)
+		return ok && equalNumbers(a, b)
+	default:
+		return a == b
+	}
+}
+
+func equalNumbers(a, b Number) bool {
+	if a == b {
+		return true
+	}
+	x, okX := new(big.Rat).SetString(a.String())
+	y, okY := new(big.Rat).SetString(b.String())
+	return okX && okY && x.Cmp(y) == 0
+}
+
+// copyDocument returns a deep copy of doc.
+func copyDocument(doc interface{}) interface{} {
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(doc))
+		for key, value := range doc {
+			c[key] = copyDocument(value)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(doc))
+		for i, value := range doc {
+			c[i] = copyDocument(value)
+		}
+		return c
+	default:
+		return doc
+	}
+}
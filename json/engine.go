@@ -1,85 +1,200 @@
 package json
 
 import (
+	"fmt"
 	"github.com/spf13/viper"
 	"io"
 	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
-// Set the best engines based on benchmarks:
+// The engines of the package-level functions. They are replaced atomically,
+// so they can be switched while other goroutines encode and decode.
+// The defaults are the best engines based on benchmarks:
 // - Marshal: Sonic (fastest)
 // - Unmarshal: Sonic (fastest)
-var marshalEngine Engine = NewSonicEngine()
-var unmarshalEngine Engine = NewSonicEngine()
+// Where sonic is not available, both are the standard engine.
+var (
+	marshalEngine   atomic.Pointer[engineRef]
+	unmarshalEngine atomic.Pointer[engineRef]
+)
+
+// engineRef holds an Engine in an atomic.Pointer.
+type engineRef struct {
+	Engine
+}
 
+func init() {
+	SetEngine(NewSonicEngine(), NewSonicEngine())
+}
+
+// SetEngineFromEnv sets the engines named by JSON_MARSHAL_ENGINE and
+// JSON_UNMARSHAL_ENGINE, and logs the engines in use. Unknown names are
+// logged and leave the engine unchanged.
 func SetEngineFromEnv() {
 	marshalEngineNam := viper.GetString("JSON_MARSHAL_ENGINE")
 	unmarshalEngineNam := viper.GetString("JSON_UNMARSHAL_ENGINE")
 	if marshalEngineNam != "" {
-		SetMarshalEngine(GetEngineByName(marshalEngineNam))
+		if engine, err := GetEngineByName(marshalEngineNam); err != nil {
+			log.Printf("Invalid JSON_MARSHAL_ENGINE: %v", err)
+		} else {
+			SetMarshalEngine(engine)
+		}
 	}
 
 	if unmarshalEngineNam != "" {
-		SetUnmarshalEngine(GetEngineByName(unmarshalEngineNam))
+		if engine, err := GetEngineByName(unmarshalEngineNam); err != nil {
+			log.Printf("Invalid JSON_UNMARSHAL_ENGINE: %v", err)
+		} else {
+			SetUnmarshalEngine(engine)
+		}
 	}
 
-	log.Printf("Using json marshal engine: %T", marshalEngine)
-	log.Printf("Using json unmarshal engine: %T", unmarshalEngine)
+	log.Printf("Using json marshal engine: %s", EngineName(MarshalEngine()))
+	log.Printf("Using json unmarshal engine: %s", EngineName(UnmarshalEngine()))
 }
 
+// EngineName returns the name of the implementation behind engine, e.g.
+// EngineStandard for NewSonicEngine where sonic is not available.
+// Registered engines can report their name with a Name() string method.
+func EngineName(engine Engine) string {
+	if named, ok := engine.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	switch engine.(type) {
+	case *stdEngine:
+		return EngineStandard
+	case *gojsonEngine:
+		return EngineGoJson
+	case *jsoniterEngine:
+		return EngineJsoniter
+	case *sonicEngine:
+		return EngineSonic
+	default:
+		return fmt.Sprintf("%T", engine)
+	}
+}
+
+// SetEngine sets the engines used by the package-level functions.
+// It is safe to call while other goroutines encode and decode.
 func SetEngine(marshal, unmarshal Engine) {
 	SetMarshalEngine(marshal)
 	SetUnmarshalEngine(unmarshal)
 }
 
+// SetMarshalEngine sets the engine of Marshal, MarshalIndent and NewEncoder.
+// A nil engine is ignored.
 func SetMarshalEngine(engine Engine) {
 	if engine != nil {
-		marshalEngine = engine
+		marshalEngine.Store(&engineRef{engine})
 	}
 }
 
+// SetUnmarshalEngine sets the engine of Unmarshal and NewDecoder.
+// A nil engine is ignored.
 func SetUnmarshalEngine(engine Engine) {
 	if engine != nil {
-		unmarshalEngine = engine
+		unmarshalEngine.Store(&engineRef{engine})
 	}
 }
 
-func GetEngineByName(name string) Engine {
-	switch name {
-	case EngineStandard:
-		return NewStdEngine()
-	case EngineGoJson:
-		return NewGoJsonEngine()
-	case EngineJsoniter:
-		return NewJsoniterEngine()
-	case EngineSonic:
-		return NewSonicEngine()
-	default:
-		return NewStdEngine()
+// MarshalEngine returns the engine of Marshal, MarshalIndent and NewEncoder.
+func MarshalEngine() Engine {
+	return marshalEngine.Load().Engine
+}
+
+// UnmarshalEngine returns the engine of Unmarshal and NewDecoder.
+func UnmarshalEngine() Engine {
+	return unmarshalEngine.Load().Engine
+}
+
+// GetEngineByName returns a new engine with the given name and the default
+// options. It returns an error for unknown names.
+func GetEngineByName(name string) (Engine, error) {
+	return NewEngine(name)
+}
+
+// scopedEngines caches the engines returned by WithEngine, by name.
+var scopedEngines sync.Map
+
+// WithEngine returns the engine with the given name and the default options,
+// to override the global engines for single calls:
+//
+//	data, err := json.WithEngine(json.EngineStandard).Marshal(v)
+//
+// The global engines are unchanged. For unknown names, the returned engine
+// fails every call with the error of GetEngineByName.
+func WithEngine(name string) Engine {
+	if engine, ok := scopedEngines.Load(name); ok {
+		return engine.(Engine)
+	}
+	engine, err := GetEngineByName(name)
+	if err != nil {
+		return errEngine{err: err}
+	}
+	actual, _ := scopedEngines.LoadOrStore(name, engine)
+	return actual.(Engine)
+}
+
+// errEngine fails every call with err.
+type errEngine struct {
+	err error
+}
+
+func (e errEngine) Marshal(interface{}) ([]byte, error)                       { return nil, e.err }
+func (e errEngine) Unmarshal([]byte, interface{}) error                       { return e.err }
+func (e errEngine) MarshalIndent(interface{}, string, string) ([]byte, error) { return nil, e.err }
+func (e errEngine) NewEncoder(io.Writer) Encoder                              { return e }
+func (e errEngine) NewDecoder(io.Reader) Decoder                              { return e }
+func (e errEngine) Encode(interface{}) error                                  { return e.err }
+func (e errEngine) Decode(interface{}) error                                  { return e.err }
+func (e errEngine) SetIndent(string, string)                                  {}
+func (e errEngine) SetEscapeHTML(bool)                                        {}
+func (e errEngine) Token() (Token, error)                                     { return nil, e.err }
+func (e errEngine) More() bool                                                { return false }
+func (e errEngine) Buffered() io.Reader                                       { return strings.NewReader("") }
+func (e errEngine) InputOffset() int64                                        { return 0 }
+func (e errEngine) UseNumber()                                                {}
+func (e errEngine) DisallowUnknownFields()                                    {}
+
+// NewEngine creates the engine registered with the given name, e.g.
+// EngineSonic, configured with opts. It returns an error wrapping
+// ErrUnsupportedOption if the engine cannot honor an option.
+//
+//	engine, err := json.NewEngine(json.EngineGoJson, json.WithUseNumber(), json.WithEscapeHTML(false))
+func NewEngine(name string, opts ...Option) (Engine, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown json engine: %s", name)
 	}
+	return factory(newOptions(opts...))
 }
 
 // Marshal serializes the input using the marshal engine.
 func Marshal(v interface{}) ([]byte, error) {
-	return marshalEngine.Marshal(v)
+	return MarshalEngine().Marshal(v)
 }
 
 // Unmarshal deserializes the input using the unmarshal engine.
 func Unmarshal(data []byte, v interface{}) error {
-	return unmarshalEngine.Unmarshal(data, v)
+	return UnmarshalEngine().Unmarshal(data, v)
 }
 
 // MarshalIndent serializes the input with indentation using the marshal engine.
 func MarshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
-	return marshalEngine.MarshalIndent(v, prefix, indent)
+	return MarshalEngine().MarshalIndent(v, prefix, indent)
 }
 
 // NewEncoder returns a new encoder using the marshal engine.
 func NewEncoder(w io.Writer) Encoder {
-	return marshalEngine.NewEncoder(w)
+	return MarshalEngine().NewEncoder(w)
 }
 
 // NewDecoder returns a new decoder using the unmarshal engine.
 func NewDecoder(r io.Reader) Decoder {
-	return unmarshalEngine.NewDecoder(r)
+	return UnmarshalEngine().NewDecoder(r)
 }
@@ -0,0 +1,230 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEngineName(t *testing.T) {
+	tests := []struct {
+		engine Engine
+		want   string
+	}{
+		{NewStdEngine(), EngineStandard},
+		{NewGoJsonEngine(), EngineGoJson},
+		{NewJsoniterEngine(), EngineJsoniter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := EngineName(tt.engine); got != tt.want {
+				t.Errorf("EngineName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSonicEngine(t *testing.T) {
+	want := EngineStandard
+	if sonic.APIKind == sonic.UseSonicJSON {
+		want = EngineSonic
+	}
+	if got := EngineName(NewSonicEngine()); got != want {
+		t.Errorf("EngineName(NewSonicEngine()) = %q, want %q", got, want)
+	}
+}
+
+type parityStruct struct {
+	Name     string            `json:"name"`
+	HTML     string            `json:"html"`
+	Unicode  string            `json:"unicode"`
+	Float    float64           `json:"float"`
+	Small    float64           `json:"small"`
+	Large    float64           `json:"large"`
+	Int      int64             `json:"int"`
+	Bytes    []byte            `json:"bytes"`
+	Time     time.Time         `json:"time"`
+	Map      map[string]int    `json:"map"`
+	Nil      *int              `json:"nil"`
+	Omit     string            `json:"omit,omitempty"`
+	Any      interface{}       `json:"any"`
+	Embedded map[string]string `json:"embedded"`
+}
+
+func TestEngineOutputParity(t *testing.T) {
+	values := map[string]interface{}{
+		"struct": parityStruct{
+			Name:     "parity",
+			HTML:     "<script>alert('x') & \"y\"</script>",
+			Unicode:  "héllo 世界   \t\n",
+			Float:    3.14159,
+			Small:    1e-7,
+			Large:    1e21,
+			Int:      -9007199254740993,
+			Bytes:    []byte("bytes"),
+			Time:     time.Date(2026, 10, 18, 12, 0, 0, 123456789, time.UTC),
+			Map:      map[string]int{"z": 26, "a": 1, "m": 13},
+			Any:      []interface{}{1.5, "two", nil, true},
+			Embedded: map[string]string{"b": "2", "a": "1"},
+		},
+		"map":   map[string]interface{}{"b": []int{1, 2}, "a": map[string]bool{"y": true, "x": false}},
+		"slice": []string{"a", "<b>"},
+		"empty": struct{}{},
+	}
+	want := NewStdEngine()
+	for _, name := range engineNames {
+		engine := newTestEngine(t, name)
+		// sonic must write the same bytes as encoding/json. go-json writes
+		// small floats as 1e-07 and jsoniter does not indent nested values,
+		// so their output is only compared once decoded.
+		same := sameJSON
+		if name == EngineStandard || name == EngineSonic {
+			same = sameBytes
+		}
+		for label, v := range values {
+			t.Run(name+"/"+label, func(t *testing.T) {
+				assertOutput(t, "Marshal", func(e Engine) ([]byte, error) { return e.Marshal(v) }, engine, want, same)
+				assertOutput(t, "MarshalIndent", func(e Engine) ([]byte, error) { return e.MarshalIndent(v, "", "  ") }, engine, want, same)
+				assertOutput(t, "Encode", func(e Engine) ([]byte, error) {
+					buf := &bytes.Buffer{}
+					err := e.NewEncoder(buf).Encode(v)
+					return buf.Bytes(), err
+				}, engine, want, same)
+			})
+		}
+	}
+}
+
+func assertOutput(t *testing.T, op string, encode func(Engine) ([]byte, error), got, want Engine, same func(got, want []byte) bool) {
+	t.Helper()
+	gotData, err := encode(got)
+	if err != nil {
+		t.Fatalf("%s error = %v", op, err)
+	}
+	wantData, err := encode(want)
+	if err != nil {
+		t.Fatalf("std %s error = %v", op, err)
+	}
+	if !same(gotData, wantData) {
+		t.Errorf("%s =\n%s\nwant (encoding/json)\n%s", op, gotData, wantData)
+	}
+}
+
+func sameBytes(got, want []byte) bool {
+	return bytes.Equal(got, want)
+}
+
+func sameJSON(got, want []byte) bool {
+	var gotValue, wantValue interface{}
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(want, &wantValue); err != nil {
+		return false
+	}
+	return cmp.Equal(gotValue, wantValue)
+}
+
+// restoreEngines restores the global engines when the test ends.
+func restoreEngines(t *testing.T) {
+	t.Helper()
+	marshal, unmarshal := MarshalEngine(), UnmarshalEngine()
+	t.Cleanup(func() { SetEngine(marshal, unmarshal) })
+}
+
+func TestGetEngineByName(t *testing.T) {
+	for _, name := range engineNames {
+		if _, err := GetEngineByName(name); err != nil {
+			t.Errorf("GetEngineByName(%s) error = %v", name, err)
+		}
+	}
+	if engine, err := GetEngineByName("simdjson"); err == nil {
+		t.Errorf("GetEngineByName(simdjson) = %s, want an error", EngineName(engine))
+	}
+}
+
+func TestWithEngine(t *testing.T) {
+	restoreEngines(t)
+	SetEngine(NewGoJsonEngine(), NewGoJsonEngine())
+
+	engine := WithEngine(EngineJsoniter)
+	if got := EngineName(engine); got != EngineJsoniter {
+		t.Errorf("EngineName(WithEngine(jsoniter)) = %q", got)
+	}
+	if engine != WithEngine(EngineJsoniter) {
+		t.Error("WithEngine() should reuse the engine of a name")
+	}
+	if _, err := engine.Marshal(map[string]int{"a": 1}); err != nil {
+		t.Errorf("Marshal() error = %v", err)
+	}
+	if got := EngineName(MarshalEngine()); got != EngineGoJson {
+		t.Errorf("WithEngine() changed the global marshal engine to %q", got)
+	}
+
+	unknown := WithEngine("simdjson")
+	if _, err := unknown.Marshal(1); err == nil {
+		t.Error("Marshal() with an unknown engine should fail")
+	}
+	if err := unknown.Unmarshal([]byte("1"), new(int)); err == nil {
+		t.Error("Unmarshal() with an unknown engine should fail")
+	}
+	if err := unknown.NewEncoder(&bytes.Buffer{}).Encode(1); err == nil {
+		t.Error("Encode() with an unknown engine should fail")
+	}
+}
+
+// TestSetEngine_Concurrent switches the global engines while other
+// goroutines encode and decode; run it with -race.
+func TestSetEngine_Concurrent(t *testing.T) {
+	restoreEngines(t)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				data, err := Marshal(map[string]int{"n": 1})
+				if err != nil {
+					t.Errorf("Marshal() error = %v", err)
+					return
+				}
+				var out map[string]int
+				if err := Unmarshal(data, &out); err != nil || out["n"] != 1 {
+					t.Errorf("Unmarshal(%s) = %v, %v", data, out, err)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 200; i++ {
+		name := engineNames[i%len(engineNames)]
+		SetEngine(WithEngine(name), WithEngine(name))
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestEngines(t *testing.T) {
+	got := map[string]bool{}
+	for _, name := range Engines() {
+		got[name] = true
+	}
+	for _, name := range engineNames {
+		if !got[name] {
+			t.Errorf("Engines() = %v, want %s registered", Engines(), name)
+		}
+	}
+}
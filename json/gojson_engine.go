@@ -1,33 +1,112 @@
 package json
 
 import (
+	"bytes"
 	"io"
 
 	gojson "github.com/goccy/go-json"
 )
 
-type gojsonEngine struct{}
+type gojsonEngine struct {
+	opts    Options
+	encOpts []gojson.EncodeOptionFunc
+}
 
 func NewGoJsonEngine() Engine {
-	return &gojsonEngine{}
+	e, _ := newGoJsonEngine(DefaultOptions())
+	return e
+}
+
+// newGoJsonEngine creates the go-json engine. go-json copies its input
+// before decoding, so decoded strings never reference the caller's buffer,
+// and turning CopyString off is not supported. It keeps invalid UTF-8 in
+// decoded strings: ValidateString replaces it before decoding.
+func newGoJsonEngine(opts Options) (Engine, error) {
+	if !opts.CopyString {
+		return nil, unsupportedOption(EngineGoJson, "strings referencing the input")
+	}
+	return &gojsonEngine{opts: opts, encOpts: gojsonEncodeOptions(opts)}, nil
+}
+
+func gojsonEncodeOptions(opts Options) []gojson.EncodeOptionFunc {
+	var encOpts []gojson.EncodeOptionFunc
+	if !opts.EscapeHTML {
+		encOpts = append(encOpts, gojson.DisableHTMLEscape())
+	}
+	if !opts.SortMapKeys {
+		encOpts = append(encOpts, gojson.UnorderedMap())
+	}
+	if !opts.ValidateString {
+		encOpts = append(encOpts, gojson.DisableNormalizeUTF8())
+	}
+	return encOpts
 }
 
 func (e *gojsonEngine) Marshal(v interface{}) ([]byte, error) {
-	return gojson.Marshal(v)
+	return gojson.MarshalWithOption(v, e.encOpts...)
 }
 
 func (e *gojsonEngine) Unmarshal(data []byte, v interface{}) error {
-	return gojson.Unmarshal(data, v)
+	if e.opts.ValidateString {
+		data = toValidUTF8(data)
+	}
+	// Unmarshal reports syntax errors, trailing data included.
+	if (!e.opts.UseNumber && !e.opts.DisallowUnknownFields) || !gojson.Valid(data) {
+		return gojson.Unmarshal(data, v)
+	}
+	return e.newDecoder(bytes.NewReader(data)).Decode(v)
 }
 
 func (e *gojsonEngine) MarshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
-	return gojson.MarshalIndent(v, prefix, indent)
+	return gojson.MarshalIndentWithOption(v, prefix, indent, e.encOpts...)
 }
 
 func (e *gojsonEngine) NewEncoder(w io.Writer) Encoder {
-	return gojson.NewEncoder(w)
+	return &gojsonEncoder{Encoder: gojson.NewEncoder(w), opts: e.opts}
 }
 
 func (e *gojsonEngine) NewDecoder(r io.Reader) Decoder {
-	return gojson.NewDecoder(r)
+	if !e.opts.ValidateString {
+		return e.newDecoder(r)
+	}
+	valid := newValidUTF8Reader(r)
+	return &gojsonDecoder{Decoder: e.newDecoder(valid), r: valid}
+}
+
+func (e *gojsonEngine) newDecoder(r io.Reader) *gojson.Decoder {
+	dec := gojson.NewDecoder(r)
+	if e.opts.UseNumber {
+		dec.UseNumber()
+	}
+	if e.opts.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	return dec
+}
+
+// gojsonEncoder applies the engine options to every Encode call.
+type gojsonEncoder struct {
+	*gojson.Encoder
+	opts Options
+}
+
+func (e *gojsonEncoder) Encode(v interface{}) error {
+	return e.EncodeWithOption(v, gojsonEncodeOptions(e.opts)...)
+}
+
+// SetEscapeHTML overrides the EscapeHTML option; go-json encoders apply
+// the options of EncodeWithOption over their own.
+func (e *gojsonEncoder) SetEscapeHTML(on bool) {
+	e.opts.EscapeHTML = on
+}
+
+// gojsonDecoder is a decoder reading through a validUTF8Reader.
+type gojsonDecoder struct {
+	*gojson.Decoder
+	r *validUTF8Reader
+}
+
+// Buffered includes the data read by the validUTF8Reader, not by the decoder yet.
+func (d *gojsonDecoder) Buffered() io.Reader {
+	return io.MultiReader(d.Decoder.Buffered(), d.r.buffered())
 }
@@ -1,6 +1,7 @@
 package json
 
 import (
+	"encoding/json"
 	"io"
 )
 
@@ -13,10 +14,42 @@ type Engine interface {
 	NewDecoder(r io.Reader) Decoder
 }
 
+// Encoder writes JSON values to a stream, as encoding/json.Encoder.
 type Encoder interface {
+	// Encode writes v followed by a newline.
 	Encode(v interface{}) error
+	// SetIndent indents the following values, as MarshalIndent.
+	// SetIndent("", "") disables indentation.
+	SetIndent(prefix, indent string)
+	// SetEscapeHTML sets whether to escape HTML characters in strings,
+	// overriding the EscapeHTML option of the engine.
+	SetEscapeHTML(on bool)
 }
 
+// Decoder reads JSON values from a stream, as encoding/json.Decoder.
 type Decoder interface {
+	// Decode reads the next value into v.
 	Decode(v interface{}) error
+	// Token returns the next token: a Delim for [ ] { }, a bool, float64,
+	// Number, string or nil, or io.EOF at the end of the input.
+	Token() (Token, error)
+	// More reports whether there is another element in the current array or object.
+	More() bool
+	// Buffered returns the data read from the stream but not decoded yet.
+	Buffered() io.Reader
+	// InputOffset returns the offset of the current position in the input.
+	InputOffset() int64
+	// UseNumber decodes numbers into interface{} values as Number.
+	UseNumber()
+	// DisallowUnknownFields rejects keys matching no struct field.
+	DisallowUnknownFields()
 }
+
+// Token is a JSON token, see Decoder.Token.
+type Token = json.Token
+
+// Delim is one of the delimiters [ ] { }.
+type Delim = json.Delim
+
+// Number is a JSON number literal, see Options.UseNumber.
+type Number = json.Number
@@ -8,10 +8,38 @@ import (
 
 type jsoniterEngine struct {
 	json jsoniter.API
+	opts Options
 }
 
 func NewJsoniterEngine() Engine {
-	return &jsoniterEngine{json: jsoniter.ConfigCompatibleWithStandardLibrary}
+	// The default options are jsoniter.ConfigCompatibleWithStandardLibrary.
+	e, _ := newJsoniterEngine(DefaultOptions())
+	return e
+}
+
+// newJsoniterEngine creates the jsoniter engine. jsoniter keeps invalid UTF-8
+// in decoded strings, so ValidateString replaces it before decoding; when
+// encoding, it replaces it if and only if HTML is escaped. jsoniter copies
+// decoded strings, so turning CopyString off is not supported.
+func newJsoniterEngine(opts Options) (Engine, error) {
+	switch {
+	case opts.ValidateString && !opts.EscapeHTML:
+		return nil, unsupportedOption(EngineJsoniter, "ValidateString without EscapeHTML")
+	case !opts.ValidateString && opts.EscapeHTML:
+		return nil, unsupportedOption(EngineJsoniter, "EscapeHTML without ValidateString")
+	case !opts.CopyString:
+		return nil, unsupportedOption(EngineJsoniter, "strings referencing the input")
+	}
+	return &jsoniterEngine{
+		json: jsoniter.Config{
+			EscapeHTML:             opts.EscapeHTML,
+			SortMapKeys:            opts.SortMapKeys,
+			ValidateJsonRawMessage: true,
+			UseNumber:              opts.UseNumber,
+			DisallowUnknownFields:  opts.DisallowUnknownFields,
+		}.Froze(),
+		opts: opts,
+	}, nil
 }
 
 func (e *jsoniterEngine) Marshal(v interface{}) ([]byte, error) {
@@ -19,6 +47,9 @@ func (e *jsoniterEngine) Marshal(v interface{}) ([]byte, error) {
 }
 
 func (e *jsoniterEngine) Unmarshal(data []byte, v interface{}) error {
+	if e.opts.ValidateString {
+		data = toValidUTF8(data)
+	}
 	return e.json.Unmarshal(data, v)
 }
 
@@ -26,10 +57,18 @@ func (e *jsoniterEngine) MarshalIndent(v interface{}, prefix, indent string) ([]
 	return e.json.MarshalIndent(v, prefix, indent)
 }
 
+// NewEncoder returns an encoder marshaling with the engine, as jsoniter
+// encoders do not indent nested values. SetEscapeHTML sets ValidateString
+// too, as jsoniter replaces invalid UTF-8 exactly when it escapes HTML.
 func (e *jsoniterEngine) NewEncoder(w io.Writer) Encoder {
-	return e.json.NewEncoder(w)
+	return newMarshalEncoder(w, e.opts, func(opts Options) (Engine, error) {
+		opts.ValidateString = opts.EscapeHTML
+		return newJsoniterEngine(opts)
+	})
 }
 
+// NewDecoder returns a decoder framing values with encoding/json, as jsoniter
+// decoders have no tokens; values are decoded with the engine.
 func (e *jsoniterEngine) NewDecoder(r io.Reader) Decoder {
-	return e.json.NewDecoder(r)
+	return newStreamDecoder(r, e.opts, newJsoniterEngine)
 }
@@ -0,0 +1,415 @@
+// Package jsontest provides a conformance suite checking that a json engine
+// follows the semantics of encoding/json.
+package jsontest
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/laziness-coders/go-utils/json"
+)
+
+// RunConformance runs the conformance suite against the engine registered
+// with name, comparing its results to encoding/json. Outputs are compared
+// once decoded, so engines may format numbers and whitespace differently;
+// map key order and HTML escaping are compared byte for byte.
+// Option tests the engine reports as unsupported are skipped, as are the
+// known deviations listed in skip, e.g. "Unmarshal/null".
+// Usage:
+//
+//	func TestConformance(t *testing.T) {
+//		jsontest.RunConformance(t, "segmentio")
+//	}
+func RunConformance(t *testing.T, name string, skip ...string) {
+	t.Helper()
+	engine, err := json.NewEngine(name)
+	if err != nil {
+		t.Fatalf("NewEngine(%s) error = %v", name, err)
+	}
+	skipped := make(map[string]bool, len(skip))
+	for _, path := range skip {
+		skipped[path] = true
+	}
+	run := func(t *testing.T, path string, f func(t *testing.T)) {
+		t.Helper()
+		t.Run(path[strings.LastIndex(path, "/")+1:], func(t *testing.T) {
+			if skipped[path] {
+				t.Skipf("known deviation of %s", name)
+			}
+			f(t)
+		})
+	}
+
+	run(t, "Marshal", func(t *testing.T) {
+		for _, tc := range marshalCases {
+			run(t, "Marshal/"+tc.name, func(t *testing.T) {
+				assertMarshal(t, engine, tc.value)
+			})
+		}
+	})
+	run(t, "MarshalErrors", func(t *testing.T) {
+		for _, tc := range marshalErrorCases {
+			run(t, "MarshalErrors/"+tc.name, func(t *testing.T) {
+				if data, err := engine.Marshal(tc.value); err == nil {
+					t.Errorf("Marshal() = %s, want an error", data)
+				}
+			})
+		}
+	})
+	run(t, "SortedMapKeys", func(t *testing.T) {
+		v := map[string]int{}
+		for i, key := range strings.Fields("q w e r t y u i o p a s d f g h j k l z x c v b n m") {
+			v[key] = i
+		}
+		want, _ := stdjson.Marshal(v)
+		if got, err := engine.Marshal(v); err != nil || !bytes.Equal(got, want) {
+			t.Errorf("Marshal() = %s, %v, want %s", got, err, want)
+		}
+	})
+	run(t, "EscapeHTML", func(t *testing.T) {
+		v := "<a href=\"x\">&</a>"
+		want, _ := stdjson.Marshal(v)
+		if got, err := engine.Marshal(v); err != nil || !bytes.Equal(got, want) {
+			t.Errorf("Marshal() = %s, %v, want %s", got, err, want)
+		}
+	})
+	run(t, "MarshalIndent", func(t *testing.T) {
+		v := map[string]interface{}{"a": []int{1, 2}, "b": map[string]string{"c": "d"}}
+		got, err := engine.MarshalIndent(v, "", "  ")
+		if err != nil {
+			t.Fatalf("MarshalIndent() error = %v", err)
+		}
+		if !bytes.Contains(got, []byte("\n  \"a\": [")) {
+			t.Errorf("MarshalIndent() = %s, want two-space indentation", got)
+		}
+		want, _ := stdjson.MarshalIndent(v, "", "  ")
+		assertSameJSON(t, got, want)
+	})
+	run(t, "Encoder", func(t *testing.T) {
+		var buf bytes.Buffer
+		enc := engine.NewEncoder(&buf)
+		for _, v := range []interface{}{map[string]int{"a": 1}, []string{"b"}} {
+			if err := enc.Encode(v); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+		}
+		if want := "{\"a\":1}\n[\"b\"]\n"; buf.String() != want {
+			t.Errorf("Encode() wrote %q, want %q", buf.String(), want)
+		}
+	})
+
+	run(t, "Unmarshal", func(t *testing.T) {
+		for _, tc := range unmarshalCases {
+			run(t, "Unmarshal/"+tc.name, func(t *testing.T) {
+				assertUnmarshal(t, engine, tc.data, tc.newValue)
+			})
+		}
+	})
+	run(t, "UnmarshalErrors", func(t *testing.T) {
+		for _, tc := range unmarshalErrorCases {
+			run(t, "UnmarshalErrors/"+tc.name, func(t *testing.T) {
+				if err := engine.Unmarshal([]byte(tc.data), tc.newValue()); err == nil {
+					t.Errorf("Unmarshal(%s) should fail", tc.data)
+				}
+			})
+		}
+		run(t, "UnmarshalErrors/non-pointer", func(t *testing.T) {
+			if err := engine.Unmarshal([]byte(`{}`), struct{}{}); err == nil {
+				t.Error("Unmarshal() into a non-pointer should fail")
+			}
+		})
+	})
+	run(t, "Decoder", func(t *testing.T) {
+		dec := engine.NewDecoder(strings.NewReader("1 \"two\"\n{\"three\":3}"))
+		var got []interface{}
+		for i := 0; i < 3; i++ {
+			var v interface{}
+			if err := dec.Decode(&v); err != nil {
+				t.Fatalf("Decode() %d error = %v", i, err)
+			}
+			got = append(got, v)
+		}
+		want := []interface{}{1.0, "two", map[string]interface{}{"three": 3.0}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Decode() = %#v, want %#v", got, want)
+		}
+	})
+	run(t, "DecoderTokens", func(t *testing.T) {
+		dec := engine.NewDecoder(strings.NewReader(`{"a": [1, {"b": true}], "c": null} 2`))
+		token := func(want json.Token) {
+			t.Helper()
+			if got, err := dec.Token(); err != nil || got != want {
+				t.Fatalf("Token() = %#v, %v, want %#v", got, err, want)
+			}
+		}
+		token(json.Delim('{'))
+		token("a")
+		token(json.Delim('['))
+		var values []interface{}
+		for dec.More() {
+			var v interface{}
+			if err := dec.Decode(&v); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			values = append(values, v)
+		}
+		if want := []interface{}{1.0, map[string]interface{}{"b": true}}; !reflect.DeepEqual(values, want) {
+			t.Errorf("Decode() = %#v, want %#v", values, want)
+		}
+		token(json.Delim(']'))
+		token("c")
+		token(nil)
+		token(json.Delim('}'))
+		token(2.0)
+		if got, err := dec.Token(); err != io.EOF {
+			t.Errorf("Token() = %#v, %v, want io.EOF", got, err)
+		}
+	})
+
+	run(t, "UseNumber", func(t *testing.T) {
+		engine := newEngineWith(t, name, json.WithUseNumber())
+		var got map[string]interface{}
+		if err := engine.Unmarshal([]byte(`{"n":12345678901234567890}`), &got); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if n, ok := got["n"].(stdjson.Number); !ok || n != "12345678901234567890" {
+			t.Errorf("Unmarshal() n = %#v, want json.Number", got["n"])
+		}
+	})
+	run(t, "DisallowUnknownFields", func(t *testing.T) {
+		engine := newEngineWith(t, name, json.WithDisallowUnknownFields())
+		var got struct{ A int }
+		if err := engine.Unmarshal([]byte(`{"A":1,"B":2}`), &got); err == nil {
+			t.Error("Unmarshal() should reject the unknown field B")
+		}
+	})
+	run(t, "NoEscapeHTML", func(t *testing.T) {
+		engine := newEngineWith(t, name, json.WithEscapeHTML(false))
+		if got, err := engine.Marshal("<&>"); err != nil || string(got) != `"<&>"` {
+			t.Errorf("Marshal() = %s, %v, want %s", got, err, `"<&>"`)
+		}
+	})
+}
+
+// newEngineWith creates the engine with opts, skipping the test if the
+// engine does not support them.
+func newEngineWith(t *testing.T, name string, opts ...json.Option) json.Engine {
+	t.Helper()
+	engine, err := json.NewEngine(name, opts...)
+	if errors.Is(err, json.ErrUnsupportedOption) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("NewEngine(%s) error = %v", name, err)
+	}
+	return engine
+}
+
+func assertMarshal(t *testing.T, engine json.Engine, v interface{}) {
+	t.Helper()
+	got, err := engine.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want, err := stdjson.Marshal(v)
+	if err != nil {
+		t.Fatalf("encoding/json Marshal() error = %v", err)
+	}
+	assertSameJSON(t, got, want)
+}
+
+// assertSameJSON compares two documents once decoded by encoding/json.
+func assertSameJSON(t *testing.T, got, want []byte) {
+	t.Helper()
+	var gotValue, wantValue interface{}
+	if err := stdjson.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("output %s is not valid JSON: %v", got, err)
+	}
+	if err := stdjson.Unmarshal(want, &wantValue); err != nil {
+		t.Fatalf("encoding/json output %s is not valid JSON: %v", want, err)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("output =\n%s\nwant (encoding/json)\n%s", got, want)
+	}
+}
+
+func assertUnmarshal(t *testing.T, engine json.Engine, data string, newValue func() interface{}) {
+	t.Helper()
+	got, want := newValue(), newValue()
+	if err := engine.Unmarshal([]byte(data), got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if err := stdjson.Unmarshal([]byte(data), want); err != nil {
+		t.Fatalf("encoding/json Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal(%s) =\n%#v\nwant (encoding/json)\n%#v", data, got, want)
+	}
+}
+
+// Embedded is promoted into Document.
+type Embedded struct {
+	Promoted string `json:"promoted"`
+	Shadowed string `json:"name"`
+}
+
+// Document covers the struct tags and field kinds of encoding/json.
+type Document struct {
+	Embedded
+	Name      string `json:"name"`
+	Renamed   int    `json:"renamed_field"`
+	Omitted   string `json:"omitted,omitempty"`
+	Skipped   string `json:"-"`
+	Dash      string `json:"-,"`
+	Quoted    int64  `json:"quoted,string"`
+	Untagged  bool
+	Pointer   *int                   `json:"pointer"`
+	Bytes     []byte                 `json:"bytes"`
+	Time      time.Time              `json:"time"`
+	NilSlice  []string               `json:"nil_slice"`
+	Empty     []string               `json:"empty"`
+	NilMap    map[string]int         `json:"nil_map"`
+	IntKeys   map[int]string         `json:"int_keys"`
+	Any       interface{}            `json:"any"`
+	Raw       stdjson.RawMessage     `json:"raw"`
+	Custom    Celsius                `json:"custom"`
+	TextKeys  map[Level]int          `json:"text_keys"`
+	Nested    map[string]interface{} `json:"nested"`
+	unexposed string
+}
+
+// Celsius implements json.Marshaler and json.Unmarshaler.
+type Celsius float64
+
+func (c Celsius) MarshalJSON() ([]byte, error) {
+	return stdjson.Marshal(map[string]float64{"celsius": float64(c)})
+}
+
+func (c *Celsius) UnmarshalJSON(data []byte) error {
+	var v map[string]float64
+	if err := stdjson.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*c = Celsius(v["celsius"])
+	return nil
+}
+
+// Level implements encoding.TextMarshaler and encoding.TextUnmarshaler,
+// used for map keys and string values.
+type Level int
+
+var levelNames = []string{"low", "medium", "high"}
+
+func (l Level) MarshalText() ([]byte, error) {
+	if l < 0 || int(l) >= len(levelNames) {
+		return nil, errors.New("invalid level")
+	}
+	return []byte(levelNames[l]), nil
+}
+
+func (l *Level) UnmarshalText(text []byte) error {
+	for i, name := range levelNames {
+		if name == string(text) {
+			*l = Level(i)
+			return nil
+		}
+	}
+	return errors.New("invalid level: " + string(text))
+}
+
+var document = Document{
+	Embedded:  Embedded{Promoted: "promoted", Shadowed: "shadowed"},
+	Name:      "héllo, 世界 <&>",
+	Renamed:   -42,
+	Skipped:   "skipped",
+	Dash:      "dash",
+	Quoted:    9007199254740993,
+	Untagged:  true,
+	Bytes:     []byte("bytes\x00"),
+	Time:      time.Date(2026, 10, 18, 12, 0, 0, 123456789, time.FixedZone("CEST", 2*60*60)),
+	Empty:     []string{},
+	IntKeys:   map[int]string{10: "ten", 2: "two", -1: "minus one"},
+	Any:       []interface{}{1.5, "two", nil, true, map[string]interface{}{"k": "v"}},
+	Raw:       stdjson.RawMessage(`{"raw":[1,2]}`),
+	Custom:    21.5,
+	TextKeys:  map[Level]int{0: 1, 2: 3},
+	Nested:    map[string]interface{}{"deep": map[string]interface{}{"deeper": []interface{}{}}},
+	unexposed: "unexposed",
+}
+
+var marshalCases = []struct {
+	name  string
+	value interface{}
+}{
+	{"struct", document},
+	{"pointer", &document},
+	{"nil", nil},
+	{"string", "line\nbreak\ttab \"quoted\" \\   \x01"},
+	{"invalid UTF-8", "a\xffb"},
+	{"floats", []float64{0, -0.0, 1e-7, 1e21, 123456789.125, math.MaxFloat64, math.SmallestNonzeroFloat64}},
+	{"integers", []interface{}{int8(-128), uint64(math.MaxUint64), int64(math.MinInt64)}},
+	{"array", [3]bool{true, false, true}},
+	{"empty struct", struct{}{}},
+}
+
+var marshalErrorCases = []struct {
+	name  string
+	value interface{}
+}{
+	{"channel", make(chan int)},
+	{"function", func() {}},
+	{"NaN", math.NaN()},
+	{"infinity", math.Inf(1)},
+	{"complex", complex(1, 2)},
+}
+
+var unmarshalCases = []struct {
+	name     string
+	data     string
+	newValue func() interface{}
+}{
+	{"struct", mustMarshal(document), func() interface{} { return &Document{} }},
+	{"case-insensitive keys", `{"NAME":"upper","Renamed_Field":7,"untagged":true}`, func() interface{} { return &Document{} }},
+	{"unknown fields", `{"name":"known","unknown":{"a":[1,2]}}`, func() interface{} { return &Document{} }},
+	{"null", `{"name":null,"pointer":null,"nil_slice":null}`, func() interface{} { return &Document{Name: "kept"} }},
+	{"interface", `{"n":1,"f":1.5,"s":"x","b":false,"z":null,"a":[{}]}`, func() interface{} { return new(interface{}) }},
+	{"escapes", `"é😀\n\"\\\/ \ud800"`, func() interface{} { return new(string) }},
+	{"invalid UTF-8", "\"a\xffb\"", func() interface{} { return new(string) }},
+	{"map", `{"b":1,"a":2}`, func() interface{} { return new(map[string]int) }},
+	{"int keys", `{"1":"one","-2":"minus two"}`, func() interface{} { return new(map[int]string) }},
+	{"text keys", `{"high":1,"low":2}`, func() interface{} { return new(map[Level]int) }},
+	{"text values", `["medium","low"]`, func() interface{} { return new([]Level) }},
+	{"slice into array", `[1,2,3,4]`, func() interface{} { return new([2]int) }},
+	{"whitespace", " \t\n{ \"name\" : \"spaced\" } \n", func() interface{} { return &Document{} }},
+}
+
+var unmarshalErrorCases = []struct {
+	name     string
+	data     string
+	newValue func() interface{}
+}{
+	{"syntax", `{"name":}`, func() interface{} { return &Document{} }},
+	{"trailing data", `{"name":"x"} {}`, func() interface{} { return &Document{} }},
+	{"truncated", `[1,2`, func() interface{} { return new([]int) }},
+	{"empty", ``, func() interface{} { return new(interface{}) }},
+	{"type mismatch", `{"renamed_field":"forty-two"}`, func() interface{} { return &Document{} }},
+	{"overflow", `300`, func() interface{} { return new(int8) }},
+	{"text unmarshaler", `["extreme"]`, func() interface{} { return new([]Level) }},
+	{"trailing comma", `[1,2,]`, func() interface{} { return new([]int) }},
+	{"single quotes", `{'a':1}`, func() interface{} { return new(map[string]int) }},
+}
+
+func mustMarshal(v interface{}) string {
+	data, err := stdjson.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
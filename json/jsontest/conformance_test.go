@@ -0,0 +1,102 @@
+package jsontest
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/laziness-coders/go-utils/json"
+	"github.com/spf13/viper"
+)
+
+// knownDeviations lists the cases in which the built-in engines differ from
+// encoding/json.
+var knownDeviations = map[string][]string{
+	// go-json matches keys to fields case-sensitively.
+	json.EngineGoJson: {"Unmarshal/case-insensitive keys"},
+	// jsoniter sets fields to their zero value on null, instead of leaving them unchanged.
+	json.EngineJsoniter: {"Unmarshal/null"},
+}
+
+func TestRunConformance(t *testing.T) {
+	for _, name := range json.Engines() {
+		t.Run(name, func(t *testing.T) {
+			skip := slices.Concat(knownDeviations[name], raceDeviations[name])
+			RunConformance(t, name, skip...)
+		})
+	}
+}
+
+// countingEngine is an instrumented wrapper, as applications register them.
+type countingEngine struct {
+	json.Engine
+	calls int
+}
+
+func (e *countingEngine) Marshal(v interface{}) ([]byte, error) {
+	e.calls++
+	return e.Engine.Marshal(v)
+}
+
+func (e *countingEngine) Name() string { return "counting" }
+
+// registerCounting registers the counting engine once, also with -count.
+var registerCounting sync.Once
+
+func TestRegister(t *testing.T) {
+	registerCounting.Do(func() {
+		json.Register("counting", func(opts json.Options) (json.Engine, error) {
+			engine, err := json.NewEngine(json.EngineStandard, json.WithOptions(opts))
+			if err != nil {
+				return nil, err
+			}
+			return &countingEngine{Engine: engine}, nil
+		})
+	})
+
+	engine, err := json.GetEngineByName("counting")
+	if err != nil {
+		t.Fatalf("GetEngineByName(counting) error = %v", err)
+	}
+	if _, err := engine.Marshal(1); err != nil || engine.(*countingEngine).calls != 1 {
+		t.Errorf("Marshal() through the registered engine: calls = %d, err = %v", engine.(*countingEngine).calls, err)
+	}
+	if got := json.EngineName(engine); got != "counting" {
+		t.Errorf("EngineName() = %q, want %q", got, "counting")
+	}
+
+	marshal, unmarshal := json.MarshalEngine(), json.UnmarshalEngine()
+	t.Cleanup(func() {
+		viper.Set("JSON_MARSHAL_ENGINE", "")
+		json.SetEngine(marshal, unmarshal)
+	})
+	viper.Set("JSON_MARSHAL_ENGINE", "counting")
+	json.SetEngineFromEnv()
+	if got := json.EngineName(json.MarshalEngine()); got != "counting" {
+		t.Errorf("SetEngineFromEnv() marshal engine = %q, want %q", got, "counting")
+	}
+
+	RunConformance(t, "counting")
+}
+
+func TestRegister_Panics(t *testing.T) {
+	factory := func(json.Options) (json.Engine, error) { return json.NewStdEngine(), nil }
+	tests := []struct {
+		name     string
+		register func()
+	}{
+		{name: "empty name", register: func() { json.Register("", factory) }},
+		{name: "nil factory", register: func() { json.Register("nil-factory", nil) }},
+		{name: "duplicate", register: func() { json.Register(json.EngineStandard, factory) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Register() should panic")
+				}
+			}()
+			tt.register()
+		})
+	}
+}
@@ -0,0 +1,6 @@
+//go:build !race
+
+package jsontest
+
+// raceDeviations lists the cases that crash under the race detector.
+var raceDeviations = map[string][]string{}
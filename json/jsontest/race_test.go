@@ -0,0 +1,11 @@
+//go:build race
+
+package jsontest
+
+import "github.com/laziness-coders/go-utils/json"
+
+// raceDeviations lists the cases that crash under the race detector.
+var raceDeviations = map[string][]string{
+	// go-json's string unescaping fails checkptr, enabled by -race.
+	json.EngineGoJson: {"Unmarshal/escapes"},
+}
@@ -0,0 +1,261 @@
+package json
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// DefaultMaxLineSize is the default maximum size of a line read by a LinesReader.
+const DefaultMaxLineSize = 1 << 20
+
+// ErrLineTooLong is reported by LinesReader for lines over the maximum size.
+var ErrLineTooLong = errors.New("json: line too long")
+
+// LineError reports the line at which reading JSON Lines failed.
+type LineError struct {
+	Line int // 1-based, blank lines included
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("json: line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// linesConfig configures LinesReader and LinesWriter.
+type linesConfig struct {
+	maxLineSize int
+	gzip        bool
+	gzipLevel   int
+}
+
+// LinesOption configures a LinesReader or LinesWriter.
+type LinesOption func(*linesConfig)
+
+func newLinesConfig(opts ...LinesOption) linesConfig {
+	cfg := linesConfig{maxLineSize: DefaultMaxLineSize, gzipLevel: gzip.DefaultCompression}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithMaxLineSize sets the maximum size in bytes of the lines read, without
+// the line break (default: DefaultMaxLineSize). Values below 1 are ignored.
+func WithMaxLineSize(size int) LinesOption {
+	return func(c *linesConfig) {
+		if size > 0 {
+			c.maxLineSize = size
+		}
+	}
+}
+
+// WithGzip makes a LinesWriter compress its output with gzip at the given
+// level, e.g. gzip.BestSpeed. LinesReader detects gzip by itself.
+func WithGzip(level int) LinesOption {
+	return func(c *linesConfig) {
+		c.gzip = true
+		c.gzipLevel = level
+	}
+}
+
+// LinesWriter writes values as JSON Lines (NDJSON), one value per line,
+// with the marshal engine. It is not safe for concurrent use.
+type LinesWriter struct {
+	w      io.Writer
+	gz     *gzip.Writer
+	engine Engine
+	err    error
+}
+
+// NewLinesWriter returns a writer of JSON Lines to w. With WithGzip, the
+// output is compressed and Close must be called to complete it.
+func NewLinesWriter(w io.Writer, opts ...LinesOption) *LinesWriter {
+	cfg := newLinesConfig(opts...)
+	lw := &LinesWriter{w: w, engine: MarshalEngine()}
+	if cfg.gzip {
+		gz, err := gzip.NewWriterLevel(w, cfg.gzipLevel)
+		if err != nil {
+			lw.err = err
+			return lw
+		}
+		lw.w, lw.gz = gz, gz
+	}
+	return lw
+}
+
+// Write writes v on its own line. Values whose MarshalJSON returns indented
+// JSON are compacted. After a write error, Write and Close return the error.
+func (w *LinesWriter) Write(v interface{}) error {
+	if w.err != nil {
+		return w.err
+	}
+	data, err := w.engine.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if bytes.IndexByte(data, '\n') >= 0 {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, data); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	}
+	_, w.err = w.w.Write(append(data, '\n'))
+	return w.err
+}
+
+// Flush writes the compressed data buffered by WithGzip to the underlying
+// writer. Without gzip, lines are not buffered and Flush does nothing.
+func (w *LinesWriter) Flush() error {
+	if w.err != nil || w.gz == nil {
+		return w.err
+	}
+	w.err = w.gz.Flush()
+	return w.err
+}
+
+// Close completes the gzip stream of WithGzip. It does not close the
+// underlying writer. Write fails after Close.
+func (w *LinesWriter) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.gz != nil {
+		if err := w.gz.Close(); err != nil {
+			w.err = err
+			return err
+		}
+	}
+	w.err = errLinesClosed
+	return nil
+}
+
+var errLinesClosed = errors.New("json: LinesWriter is closed")
+
+// LinesReader reads values from JSON Lines (NDJSON), one value per line,
+// with the unmarshal engine. Blank lines are skipped, and gzip input is
+// detected by its magic bytes and decompressed. It is not safe for
+// concurrent use.
+type LinesReader struct {
+	r       io.Reader
+	cfg     linesConfig
+	engine  Engine
+	scanner *bufio.Scanner
+	line    int
+	err     error
+}
+
+// NewLinesReader returns a reader of JSON Lines from r. Nothing is read
+// before the first call to Decode.
+func NewLinesReader(r io.Reader, opts ...LinesOption) *LinesReader {
+	return &LinesReader{r: r, cfg: newLinesConfig(opts...), engine: UnmarshalEngine()}
+}
+
+// Decode reads the next non-blank line into v. It returns io.EOF at the end
+// of the input, and a *LineError for lines that are too long or fail to
+// decode. Decoding can continue after a line fails to decode; other errors
+// are returned again by every call.
+func (r *LinesReader) Decode(v interface{}) error {
+	if r.scanner == nil && r.err == nil {
+		r.err = r.init()
+	}
+	if r.err != nil {
+		return r.err
+	}
+	for r.scanner.Scan() {
+		r.line++
+		if r.scanner.Err() != nil {
+			// A partial line before a read error, e.g. of truncated gzip input.
+			r.err = &LineError{Line: r.line, Err: r.scanner.Err()}
+			return r.err
+		}
+		// Without its line break, which the buffer leaves room for.
+		line := r.scanner.Bytes()
+		if len(line) > r.cfg.maxLineSize {
+			r.err = &LineError{Line: r.line, Err: ErrLineTooLong}
+			return r.err
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		// The scanner reuses its buffer, which engines without CopyString
+		// would reference.
+		if err := r.engine.Unmarshal(bytes.Clone(line), v); err != nil {
+			return &LineError{Line: r.line, Err: err}
+		}
+		return nil
+	}
+	r.err = r.scanner.Err()
+	switch {
+	case r.err == nil:
+		r.err = io.EOF
+	case errors.Is(r.err, bufio.ErrTooLong):
+		r.err = &LineError{Line: r.line + 1, Err: ErrLineTooLong}
+	default:
+		r.err = &LineError{Line: r.line + 1, Err: r.err}
+	}
+	return r.err
+}
+
+// Line returns the number of the last line read, blank lines included.
+func (r *LinesReader) Line() int {
+	return r.line
+}
+
+// init decompresses gzip input and sets up the scanner.
+func (r *LinesReader) init() error {
+	br := bufio.NewReader(r.r)
+	var src io.Reader = br
+	magic, err := br.Peek(2)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return fmt.Errorf("json: gzip: %w", err)
+		}
+		src = gz
+	}
+	r.scanner = bufio.NewScanner(src)
+	// Two more bytes for a \r\n line break, which the scanner trims; Decode
+	// checks the size of the line without it.
+	r.scanner.Buffer(make([]byte, 0, min(r.cfg.maxLineSize+2, 64*1024)), r.cfg.maxLineSize+2)
+	return nil
+}
+
+// Lines returns an iterator over the values read by r, decoded as T:
+//
+//	for event, err := range json.Lines[Event](json.NewLinesReader(file)) {
+//		if err != nil {
+//			return err // or continue, to skip lines that fail to decode
+//		}
+//		handle(event)
+//	}
+//
+// Lines that fail to decode are yielded with their *LineError, and the
+// iteration continues if the loop does. It stops after other errors.
+func Lines[T any](r *LinesReader) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			var v T
+			err := r.Decode(&v)
+			if err == io.EOF {
+				return
+			}
+			if !yield(v, err) || r.err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package json
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+type lineEvent struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// indentedValue marshals itself over several lines.
+type indentedValue struct{}
+
+func (indentedValue) MarshalJSON() ([]byte, error) {
+	return []byte("{\n  \"a\": [\n    1,\n    2\n  ]\n}"), nil
+}
+
+func TestLinesWriter(t *testing.T) {
+	for _, name := range engineNames {
+		t.Run(name, func(t *testing.T) {
+			restoreEngines(t)
+			SetMarshalEngine(newTestEngine(t, name))
+
+			var buf bytes.Buffer
+			w := NewLinesWriter(&buf)
+			for _, v := range []interface{}{lineEvent{ID: 1, Name: "a\nb"}, indentedValue{}, []int{1}} {
+				if err := w.Write(v); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := w.Write(make(chan int)); err == nil {
+				t.Error("Write(chan) should fail")
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			want := `{"id":1,"name":"a\nb"}` + "\n" + `{"a":[1,2]}` + "\n" + `[1]` + "\n"
+			if buf.String() != want {
+				t.Errorf("output = %q, want %q", buf.String(), want)
+			}
+			if err := w.Write(1); err == nil {
+				t.Error("Write() after Close should fail")
+			}
+		})
+	}
+
+	writeErr := errors.New("write failed")
+	w := NewLinesWriter(errWriter{writeErr})
+	if err := w.Write(1); !errors.Is(err, writeErr) {
+		t.Errorf("Write() error = %v, want %v", err, writeErr)
+	}
+	if err := w.Close(); !errors.Is(err, writeErr) {
+		t.Errorf("Close() error = %v, want %v", err, writeErr)
+	}
+}
+
+func TestLinesReader(t *testing.T) {
+	input := "\n" +
+		`{"id":1,"name":"first"}` + "\r\n" +
+		"   \t\n" +
+		`{"id":2,"name":"second"}` + "\n" +
+		`{"id":3,"name":"third"}` // no final line break
+
+	for _, name := range engineNames {
+		t.Run(name, func(t *testing.T) {
+			restoreEngines(t)
+			SetUnmarshalEngine(newTestEngine(t, name))
+
+			r := NewLinesReader(iotest.OneByteReader(strings.NewReader(input)))
+			var got []lineEvent
+			var lines []int
+			for event, err := range Lines[lineEvent](r) {
+				if err != nil {
+					t.Fatalf("Lines() error = %v", err)
+				}
+				got = append(got, event)
+				lines = append(lines, r.Line())
+			}
+			want := []lineEvent{{1, "first"}, {2, "second"}, {3, "third"}}
+			if len(got) != len(want) {
+				t.Fatalf("Lines() = %+v, want %+v", got, want)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("Lines()[%d] = %+v, want %+v", i, got[i], want[i])
+				}
+			}
+			if wantLines := []int{2, 4, 5}; !equalInts(lines, wantLines) {
+				t.Errorf("Line() = %v, want %v", lines, wantLines)
+			}
+			var v lineEvent
+			if err := r.Decode(&v); err != io.EOF {
+				t.Errorf("Decode() at the end error = %v, want io.EOF", err)
+			}
+		})
+	}
+}
+
+func TestLinesReader_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		opts      []LinesOption
+		wantIDs   []int
+		wantLines []int // of the errors
+		wantErr   error
+	}{
+		{
+			name:      "invalid lines are skipped",
+			input:     "{\"id\":1}\nnot json\n\n{\"id\":\"two\"}\n{\"id\":3}\n",
+			wantIDs:   []int{1, 3},
+			wantLines: []int{2, 4},
+		},
+		{
+			name:      "line too long stops",
+			input:     "{\"id\":1}\n{\"id\":2,\"name\":\"too long\"}\n{\"id\":3}\n",
+			opts:      []LinesOption{WithMaxLineSize(16)},
+			wantIDs:   []int{1},
+			wantLines: []int{2},
+			wantErr:   ErrLineTooLong,
+		},
+		{
+			name:    "line at the maximum size",
+			input:   "{\"id\":1}\n{\"id\":2}",
+			opts:    []LinesOption{WithMaxLineSize(8)},
+			wantIDs: []int{1, 2},
+		},
+		{
+			name:    "crlf line at the maximum size",
+			input:   "{\"id\":1}\r\n{\"id\":2}\r\n",
+			opts:    []LinesOption{WithMaxLineSize(8)},
+			wantIDs: []int{1, 2},
+		},
+		{
+			name:      "line one byte too long",
+			input:     "{\"id\":1}\n{\"id\":22}\n{\"id\":3}\n",
+			opts:      []LinesOption{WithMaxLineSize(8)},
+			wantIDs:   []int{1},
+			wantLines: []int{2},
+			wantErr:   ErrLineTooLong,
+		},
+		{
+			name:      "crlf line one byte too long",
+			input:     "{\"id\":1}\r\n{\"id\":22}\r\n",
+			opts:      []LinesOption{WithMaxLineSize(8)},
+			wantIDs:   []int{1},
+			wantLines: []int{2},
+			wantErr:   ErrLineTooLong,
+		},
+		{
+			name:      "truncated gzip stops",
+			input:     string(gzipData(t, "{\"id\":1}\n{\"id\":2}\n")[:20]),
+			wantLines: []int{1},
+			wantErr:   io.ErrUnexpectedEOF,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids, lines []int
+			var lastErr error
+			for event, err := range Lines[lineEvent](NewLinesReader(strings.NewReader(tt.input), tt.opts...)) {
+				if err != nil {
+					var lineErr *LineError
+					if !errors.As(err, &lineErr) {
+						t.Fatalf("Lines() error = %v, want a *LineError", err)
+					}
+					lines = append(lines, lineErr.Line)
+					lastErr = err
+					continue
+				}
+				ids = append(ids, event.ID)
+			}
+			if !equalInts(ids, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+			if !equalInts(lines, tt.wantLines) {
+				t.Errorf("error lines = %v, want %v", lines, tt.wantLines)
+			}
+			if tt.wantErr != nil && !errors.Is(lastErr, tt.wantErr) {
+				t.Errorf("last error = %v, want %v", lastErr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLinesReader_Break(t *testing.T) {
+	r := NewLinesReader(strings.NewReader("1\n2\n3\n"))
+	for n, err := range Lines[int](r) {
+		if err != nil || n != 1 {
+			t.Fatalf("Lines() = %d, %v, want 1", n, err)
+		}
+		break
+	}
+	var n int
+	if err := r.Decode(&n); err != nil || n != 2 {
+		t.Errorf("Decode() after break = %d, %v, want 2", n, err)
+	}
+}
+
+func TestLines_Gzip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewLinesWriter(&buf, WithGzip(gzip.BestSpeed))
+	for i := 1; i <= 3; i++ {
+		if err := w.Write(lineEvent{ID: i}); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte{0x1f, 0x8b}) {
+		t.Fatalf("output is not gzip: %q", buf.Bytes())
+	}
+
+	var ids []int
+	for event, err := range Lines[lineEvent](NewLinesReader(&buf)) {
+		if err != nil {
+			t.Fatalf("Lines() error = %v", err)
+		}
+		ids = append(ids, event.ID)
+	}
+	if !equalInts(ids, []int{1, 2, 3}) {
+		t.Errorf("ids = %v, want [1 2 3]", ids)
+	}
+
+	if err := NewLinesReader(strings.NewReader("")).Decode(new(int)); err != io.EOF {
+		t.Errorf("Decode() of empty input error = %v, want io.EOF", err)
+	}
+	var lineErr *LineError
+	if err := NewLinesReader(strings.NewReader("\x1f")).Decode(new(int)); !errors.As(err, &lineErr) {
+		t.Errorf("Decode() of a single magic byte error = %v, want a *LineError", err)
+	}
+	if err := NewLinesReader(strings.NewReader("\x1f\x8b\x00")).Decode(new(int)); err == nil || err == io.EOF {
+		t.Errorf("Decode() of an invalid gzip header error = %v", err)
+	}
+}
+
+func gzipData(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(data)); err != nil {
+		t.Fatalf("gzip Write() error = %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip Close() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func equalInts(got, want []int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
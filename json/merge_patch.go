@@ -0,0 +1,119 @@
+package json
+
+import "fmt"
+
+// ApplyMergePatch applies the JSON Merge Patch patch (RFC 7386) to the JSON
+// document doc: members of patch objects replace those of doc, recursively,
+// and null members remove them.
+//
+//	patched, err := json.ApplyMergePatch(doc, []byte(`{"replicas": 3, "debug": null}`))
+//
+// The documents are decoded with the unmarshal engine and the result is
+// encoded with the marshal engine.
+func ApplyMergePatch(doc, patch []byte) ([]byte, error) {
+	target, err := parseDocument(doc)
+	if err != nil {
+		return nil, fmt.Errorf("json: document: %w", err)
+	}
+	p, err := parseDocument(patch)
+	if err != nil {
+		return nil, fmt.Errorf("json: %w: %v", ErrInvalidPatch, err)
+	}
+	return Marshal(mergePatch(target, p))
+}
+
+// ApplyMergePatchValue applies the JSON Merge Patch patch to v, as encoded
+// by the marshal engine, and decodes the result into a new T with the
+// unmarshal engine. v is unchanged.
+func ApplyMergePatchValue[T any](v T, patch []byte) (T, error) {
+	var zero T
+	p, err := parseDocument(patch)
+	if err != nil {
+		return zero, fmt.Errorf("json: %w: %v", ErrInvalidPatch, err)
+	}
+	target, err := toDocument(v)
+	if err != nil {
+		return zero, err
+	}
+	return fromDocument[T](mergePatch(target, p))
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = map[string]interface{}{}
+	}
+	for key, value := range p {
+		if value == nil {
+			delete(t, key)
+		} else {
+			t[key] = mergePatch(t[key], value)
+		}
+	}
+	return t
+}
+
+// CreateMergePatch returns a JSON Merge Patch turning the JSON document
+// original into modified. Merge patches cannot set members to null, which
+// removes them, and replace arrays as a whole: use CreatePatch where this
+// matters.
+func CreateMergePatch(original, modified []byte) ([]byte, error) {
+	a, err := parseDocument(original)
+	if err != nil {
+		return nil, fmt.Errorf("json: original: %w", err)
+	}
+	b, err := parseDocument(modified)
+	if err != nil {
+		return nil, fmt.Errorf("json: modified: %w", err)
+	}
+	return Marshal(createMergePatch(a, b))
+}
+
+// CreateMergePatchValues returns a JSON Merge Patch turning original into
+// modified, as encoded by the marshal engine, see CreateMergePatch.
+func CreateMergePatchValues(original, modified interface{}) ([]byte, error) {
+	a, err := toDocument(original)
+	if err != nil {
+		return nil, err
+	}
+	b, err := toDocument(modified)
+	if err != nil {
+		return nil, err
+	}
+	return Marshal(createMergePatch(a, b))
+}
+
+func createMergePatch(original, modified interface{}) interface{} {
+	a, okA := original.(map[string]interface{})
+	b, okB := modified.(map[string]interface{})
+	if !okA || !okB {
+		return modified
+	}
+	patch := map[string]interface{}{}
+	for key := range a {
+		if _, ok := b[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	for key, value := range b {
+		current, ok := a[key]
+		switch {
+		case !ok:
+			patch[key] = value
+		case equalDocuments(current, value):
+		default:
+			if _, isObject := value.(map[string]interface{}); isObject {
+				if _, wasObject := current.(map[string]interface{}); wasObject {
+					patch[key] = createMergePatch(current, value)
+					continue
+				}
+			}
+			patch[key] = value
+		}
+	}
+	return patch
+}
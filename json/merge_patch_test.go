@@ -0,0 +1,110 @@
+package json
+
+import "testing"
+
+func TestApplyMergePatch(t *testing.T) {
+	// The examples of RFC 7386, appendix A.
+	tests := []struct {
+		doc   string
+		patch string
+		want  string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, name := range engineNames {
+		t.Run(name, func(t *testing.T) {
+			restoreEngines(t)
+			engine := newTestEngine(t, name)
+			SetEngine(engine, engine)
+
+			for _, tt := range tests {
+				t.Run(tt.patch, func(t *testing.T) {
+					got, err := ApplyMergePatch([]byte(tt.doc), []byte(tt.patch))
+					if err != nil {
+						t.Fatalf("ApplyMergePatch() error = %v", err)
+					}
+					if string(got) != tt.want {
+						t.Errorf("ApplyMergePatch(%s, %s) = %s, want %s", tt.doc, tt.patch, got, tt.want)
+					}
+				})
+			}
+		})
+	}
+
+	if _, err := ApplyMergePatch([]byte(`{}`), []byte(`{`)); err == nil {
+		t.Error("ApplyMergePatch() should reject an invalid patch")
+	}
+}
+
+func TestApplyMergePatchValue(t *testing.T) {
+	original := patchTarget{Name: "api", Replicas: 2, Labels: map[string]string{"team": "core", "tier": "web"}}
+	got, err := ApplyMergePatchValue(original, []byte(`{"replicas":3,"labels":{"tier":null},"ports":[443]}`))
+	if err != nil {
+		t.Fatalf("ApplyMergePatchValue() error = %v", err)
+	}
+	if got.Name != "api" || got.Replicas != 3 || len(got.Labels) != 1 || got.Labels["team"] != "core" || len(got.Ports) != 1 {
+		t.Errorf("ApplyMergePatchValue() = %+v", got)
+	}
+	if len(original.Labels) != 2 {
+		t.Errorf("ApplyMergePatchValue() changed the original: %+v", original)
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		modified string
+		want     string
+	}{
+		{name: "equal", original: `{"a":{"b":[1]}}`, modified: `{"a":{"b":[1.0]}}`, want: `{}`},
+		{name: "members", original: `{"a":1,"b":2,"c":{"d":1,"e":2}}`, modified: `{"a":1,"b":3,"c":{"d":1},"f":"new"}`, want: `{"b":3,"c":{"e":null},"f":"new"}`},
+		{name: "arrays as a whole", original: `{"a":[1,2,3]}`, modified: `{"a":[1,2]}`, want: `{"a":[1,2]}`},
+		{name: "object replaced", original: `{"a":"x"}`, modified: `{"a":{"b":1}}`, want: `{"a":{"b":1}}`},
+		{name: "not objects", original: `[1]`, modified: `[2]`, want: `[2]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateMergePatch([]byte(tt.original), []byte(tt.modified))
+			if err != nil {
+				t.Fatalf("CreateMergePatch() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("CreateMergePatch() = %s, want %s", got, tt.want)
+			}
+			patched, err := ApplyMergePatch([]byte(tt.original), got)
+			if err != nil {
+				t.Fatalf("ApplyMergePatch() error = %v", err)
+			}
+			if changes, _ := Diff(patched, []byte(tt.modified)); len(changes) != 0 {
+				t.Errorf("ApplyMergePatch() = %s, want %s", patched, tt.modified)
+			}
+		})
+	}
+}
+
+func TestCreateMergePatchValues(t *testing.T) {
+	original := patchTarget{Name: "api", Replicas: 2, Labels: map[string]string{"team": "core"}}
+	modified := patchTarget{Name: "api", Replicas: 2, Ports: []int{80}}
+	got, err := CreateMergePatchValues(original, modified)
+	if err != nil {
+		t.Fatalf("CreateMergePatchValues() error = %v", err)
+	}
+	if want := `{"labels":null,"ports":[80]}`; string(got) != want {
+		t.Errorf("CreateMergePatchValues() = %s, want %s", got, want)
+	}
+}
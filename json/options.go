@@ -0,0 +1,108 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupportedOption is returned by NewEngine for options the engine cannot honor.
+var ErrUnsupportedOption = errors.New("unsupported json option")
+
+// Options configures the behavior of an engine, the same way for every engine.
+// The defaults match encoding/json. Engines report the options they cannot
+// honor as an error wrapping ErrUnsupportedOption, rather than ignoring them.
+type Options struct {
+	// UseNumber decodes numbers into interface{} values as Number instead of float64.
+	UseNumber bool
+	// DisallowUnknownFields makes decoding into a struct fail on keys
+	// matching no exported field.
+	DisallowUnknownFields bool
+	// EscapeHTML escapes <, > and & in strings as \u003c, \u003e and \u0026
+	// (default: true).
+	EscapeHTML bool
+	// SortMapKeys writes map keys in sorted order (default: true). Without
+	// it, map keys are written in iteration order, skipping the sort.
+	SortMapKeys bool
+	// ValidateString replaces invalid UTF-8 in strings with U+FFFD, when
+	// encoding and decoding (default: true). Without it, invalid bytes are
+	// copied as is.
+	ValidateString bool
+	// CopyString makes decoded strings independent of the input (default:
+	// true). Without it, decoded strings reference the input buffer, which
+	// must then not be modified.
+	CopyString bool
+}
+
+// Option sets a field of Options.
+type Option func(*Options)
+
+// DefaultOptions returns the options matching encoding/json.
+func DefaultOptions() Options {
+	return Options{
+		EscapeHTML:     true,
+		SortMapKeys:    true,
+		ValidateString: true,
+		CopyString:     true,
+	}
+}
+
+func newOptions(opts ...Option) Options {
+	o := DefaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithOptions replaces all options, e.g. with options decoded from a config file.
+func WithOptions(options Options) Option {
+	return func(o *Options) {
+		*o = options
+	}
+}
+
+// WithUseNumber decodes numbers into interface{} values as Number.
+func WithUseNumber() Option {
+	return func(o *Options) {
+		o.UseNumber = true
+	}
+}
+
+// WithDisallowUnknownFields rejects keys matching no struct field when decoding.
+func WithDisallowUnknownFields() Option {
+	return func(o *Options) {
+		o.DisallowUnknownFields = true
+	}
+}
+
+// WithEscapeHTML sets whether to escape HTML characters in strings.
+func WithEscapeHTML(escape bool) Option {
+	return func(o *Options) {
+		o.EscapeHTML = escape
+	}
+}
+
+// WithSortMapKeys sets whether to write map keys in sorted order.
+func WithSortMapKeys(sort bool) Option {
+	return func(o *Options) {
+		o.SortMapKeys = sort
+	}
+}
+
+// WithValidateString sets whether to replace invalid UTF-8 in strings.
+func WithValidateString(validate bool) Option {
+	return func(o *Options) {
+		o.ValidateString = validate
+	}
+}
+
+// WithCopyString sets whether decoded strings are copied from the input.
+func WithCopyString(copyString bool) Option {
+	return func(o *Options) {
+		o.CopyString = copyString
+	}
+}
+
+func unsupportedOption(engine, option string) error {
+	return fmt.Errorf("%w: %s engine does not support %s", ErrUnsupportedOption, engine, option)
+}
@@ -0,0 +1,273 @@
+package json
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// newTestEngine creates the named engine with opts, failing the test on error.
+func newTestEngine(t *testing.T, name string, opts ...Option) Engine {
+	t.Helper()
+	engine, err := NewEngine(name, opts...)
+	if err != nil {
+		t.Fatalf("NewEngine(%s) error = %v", name, err)
+	}
+	return engine
+}
+
+func TestNewEngine(t *testing.T) {
+	for _, name := range engineNames {
+		t.Run(name, func(t *testing.T) {
+			want := name
+			if name == EngineSonic {
+				want = EngineName(NewSonicEngine()) // std where sonic is not available
+			}
+			if got := EngineName(newTestEngine(t, name)); got != want {
+				t.Errorf("EngineName() = %q, want %q", got, want)
+			}
+		})
+	}
+
+	if _, err := NewEngine("unknown"); err == nil {
+		t.Error("NewEngine(unknown) should fail")
+	}
+}
+
+// newSupportedEngine creates the named engine with opts, skipping the test
+// if the engine does not support them.
+func newSupportedEngine(t *testing.T, name string, opts ...Option) Engine {
+	t.Helper()
+	engine, err := NewEngine(name, opts...)
+	if errors.Is(err, ErrUnsupportedOption) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("NewEngine(%s) error = %v", name, err)
+	}
+	return engine
+}
+
+func TestNewEngine_UnsupportedOption(t *testing.T) {
+	// Where sonic is not available, its engine is the standard one.
+	sonicFallback := !sonicAvailable()
+
+	tests := []struct {
+		name    string
+		engine  string
+		opts    []Option
+		wantErr bool
+	}{
+		{name: "std unsorted", engine: EngineStandard, opts: []Option{WithSortMapKeys(false)}, wantErr: true},
+		{name: "std invalid UTF-8", engine: EngineStandard, opts: []Option{WithValidateString(false)}, wantErr: true},
+		{name: "std no copy", engine: EngineStandard, opts: []Option{WithCopyString(false)}, wantErr: true},
+		{name: "std raw HTML", engine: EngineStandard, opts: []Option{WithEscapeHTML(false)}},
+		{name: "gojson no copy", engine: EngineGoJson, opts: []Option{WithCopyString(false)}, wantErr: true},
+		{name: "gojson unsorted", engine: EngineGoJson, opts: []Option{WithSortMapKeys(false), WithValidateString(false)}},
+		{name: "jsoniter raw HTML", engine: EngineJsoniter, opts: []Option{WithEscapeHTML(false)}, wantErr: true},
+		{name: "jsoniter invalid UTF-8", engine: EngineJsoniter, opts: []Option{WithValidateString(false)}, wantErr: true},
+		{name: "jsoniter no copy", engine: EngineJsoniter, opts: []Option{WithCopyString(false)}, wantErr: true},
+		{name: "jsoniter raw", engine: EngineJsoniter, opts: []Option{WithEscapeHTML(false), WithValidateString(false)}},
+		{
+			name:    "sonic",
+			engine:  EngineSonic,
+			opts:    []Option{WithSortMapKeys(false), WithValidateString(false), WithCopyString(false)},
+			wantErr: sonicFallback,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEngine(tt.engine, tt.opts...)
+			if tt.wantErr && !errors.Is(err, ErrUnsupportedOption) {
+				t.Errorf("NewEngine() error = %v, want ErrUnsupportedOption", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("NewEngine() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestOptions_UseNumber(t *testing.T) {
+	for _, name := range engineNames {
+		t.Run(name, func(t *testing.T) {
+			engine := newTestEngine(t, name, WithUseNumber())
+			data := []byte(`{"n":12345678901234567890}`)
+
+			var got map[string]interface{}
+			if err := engine.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if n, ok := got["n"].(stdjson.Number); !ok || n.String() != "12345678901234567890" {
+				t.Errorf("Unmarshal() n = %#v, want Number 12345678901234567890", got["n"])
+			}
+
+			got = nil
+			if err := engine.NewDecoder(bytes.NewReader(data)).Decode(&got); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if _, ok := got["n"].(stdjson.Number); !ok {
+				t.Errorf("Decode() n = %#v, want Number", got["n"])
+			}
+
+			if err := engine.Unmarshal([]byte(`{"n":1} x`), &got); err == nil {
+				t.Error("Unmarshal() should reject trailing data")
+			}
+		})
+	}
+}
+
+func TestOptions_DisallowUnknownFields(t *testing.T) {
+	type target struct {
+		A int `json:"a"`
+	}
+	data := []byte(`{"a":1,"b":2}`)
+	for _, name := range engineNames {
+		t.Run(name, func(t *testing.T) {
+			engine := newTestEngine(t, name, WithDisallowUnknownFields())
+			var got target
+			if err := engine.Unmarshal(data, &got); err == nil {
+				t.Error("Unmarshal() should reject unknown field b")
+			}
+			if err := engine.NewDecoder(bytes.NewReader(data)).Decode(&got); err == nil {
+				t.Error("Decode() should reject unknown field b")
+			}
+			if err := newTestEngine(t, name).Unmarshal(data, &got); err != nil || got.A != 1 {
+				t.Errorf("Unmarshal() without the option = %+v, %v", got, err)
+			}
+		})
+	}
+}
+
+func TestOptions_EscapeHTML(t *testing.T) {
+	v := map[string]string{"html": "<a href=\"x\">&</a>"}
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{name: "escaped", want: `{"html":"\u003ca href=\"x\"\u003e\u0026\u003c/a\u003e"}`},
+		{name: "raw", opts: []Option{WithEscapeHTML(false)}, want: `{"html":"<a href=\"x\">&</a>"}`},
+		{name: "raw unvalidated", opts: []Option{WithEscapeHTML(false), WithValidateString(false)}, want: `{"html":"<a href=\"x\">&</a>"}`},
+	}
+	for _, name := range engineNames {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				engine := newSupportedEngine(t, name, tt.opts...)
+				data, err := engine.Marshal(v)
+				if err != nil {
+					t.Fatalf("Marshal() error = %v", err)
+				}
+				if string(data) != tt.want {
+					t.Errorf("Marshal() = %s, want %s", data, tt.want)
+				}
+
+				data, err = engine.MarshalIndent(v, "", "  ")
+				if err != nil {
+					t.Fatalf("MarshalIndent() error = %v", err)
+				}
+				var compact bytes.Buffer
+				if err := stdjson.Compact(&compact, data); err != nil || compact.String() != tt.want {
+					t.Errorf("MarshalIndent() = %s, want %s", data, tt.want)
+				}
+
+				var buf bytes.Buffer
+				if err := engine.NewEncoder(&buf).Encode(v); err != nil {
+					t.Fatalf("Encode() error = %v", err)
+				}
+				if buf.String() != tt.want+"\n" {
+					t.Errorf("Encode() = %s, want %s", buf.String(), tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestOptions_SortMapKeys(t *testing.T) {
+	v := map[string]int{}
+	for _, key := range strings.Split("q w e r t y u i o p a s d f g h j k l z x c v b n m", " ") {
+		v[key] = len(v)
+	}
+	want, _ := stdjson.Marshal(v)
+	for _, name := range engineNames {
+		t.Run(name, func(t *testing.T) {
+			data, err := newTestEngine(t, name, WithSortMapKeys(true)).Marshal(v)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if !bytes.Equal(data, want) {
+				t.Errorf("Marshal() = %s, want %s", data, want)
+			}
+
+			data, err = newSupportedEngine(t, name, WithSortMapKeys(false)).Marshal(v)
+			if err != nil {
+				t.Fatalf("Marshal() unsorted error = %v", err)
+			}
+			var got map[string]int
+			if err := stdjson.Unmarshal(data, &got); err != nil || len(got) != len(v) {
+				t.Errorf("Marshal() unsorted = %s, %v", data, err)
+			}
+		})
+	}
+}
+
+func TestOptions_ValidateString(t *testing.T) {
+	const want = "a\uFFFD\uFFFDb 世界"
+	for _, name := range engineNames {
+		t.Run(name, func(t *testing.T) {
+			engine := newTestEngine(t, name, WithValidateString(true))
+
+			data, err := engine.Marshal("a\xff\xfeb 世界")
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			var encoded string
+			if err := stdjson.Unmarshal(data, &encoded); err != nil || encoded != want || !bytes.Equal(data, bytes.ToValidUTF8(data, nil)) {
+				t.Errorf("Marshal() = %q, want valid UTF-8 %q", data, want)
+			}
+
+			input := []byte("\"a\xff\xfeb 世界\"")
+			var got string
+			if err := engine.Unmarshal(input, &got); err != nil || got != want {
+				t.Errorf("Unmarshal() = %q, %v, want %q", got, err, want)
+			}
+			// Read byte by byte to split the multi-byte runes.
+			got = ""
+			if err := engine.NewDecoder(iotest.OneByteReader(bytes.NewReader(input))).Decode(&got); err != nil || got != want {
+				t.Errorf("Decode() = %q, %v, want %q", got, err, want)
+			}
+		})
+	}
+}
+
+func TestOptions_CopyString(t *testing.T) {
+	for _, name := range engineNames {
+		t.Run(name, func(t *testing.T) {
+			data := []byte(`{"name":"original"}`)
+			var got struct {
+				Name string `json:"name"`
+			}
+			if err := newTestEngine(t, name, WithCopyString(true)).Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			copy(data, bytes.Repeat([]byte("x"), len(data)))
+			if got.Name != "original" {
+				t.Errorf("Name = %q after the input was modified, want %q", got.Name, "original")
+			}
+		})
+	}
+}
+
+func TestValidUTF8Reader(t *testing.T) {
+	input := "héllo\xff 世界\xe4\xb8"
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(newValidUTF8Reader(iotest.OneByteReader(strings.NewReader(input)))); err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+	if want := "héllo\uFFFD 世界\uFFFD\uFFFD"; buf.String() != want {
+		t.Errorf("read %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,415 @@
+package json
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// JSON Patch operations (RFC 6902).
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpCopy    = "copy"
+	OpTest    = "test"
+)
+
+var (
+	// ErrInvalidPatch is reported for malformed patches and operations.
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrPathNotFound is reported for operations on paths missing from the document.
+	ErrPathNotFound = errors.New("path not found")
+	// ErrTestFailed is reported for test operations whose value differs.
+	ErrTestFailed = errors.New("test failed")
+)
+
+// RawMessage is a raw encoded JSON value, as encoding/json.RawMessage.
+type RawMessage = json.RawMessage
+
+// Operation is a JSON Patch operation. Path and From are JSON Pointers
+// (RFC 6901), e.g. /spec/containers/0/image.
+type Operation struct {
+	Op    string     `json:"op"`
+	Path  string     `json:"path"`
+	From  string     `json:"from,omitempty"`  // move and copy
+	Value RawMessage `json:"value,omitempty"` // add, replace and test
+}
+
+// Patch is a JSON Patch document (RFC 6902), applied operation by operation.
+type Patch []Operation
+
+// PatchError reports the operation of a patch that failed.
+type PatchError struct {
+	Index     int // of the operation in the patch
+	Operation Operation
+	Err       error
+}
+
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("json: patch operation %d (%s %s): %v", e.Index, e.Operation.Op, e.Operation.Path, e.Err)
+}
+
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// DecodePatch decodes a JSON Patch document with the unmarshal engine and
+// checks that its operations have the members they require.
+func DecodePatch(data []byte) (Patch, error) {
+	doc, err := parseDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("json: %w: %v", ErrInvalidPatch, err)
+	}
+	operations, ok := doc.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("json: %w: not an array", ErrInvalidPatch)
+	}
+	patch := make(Patch, len(operations))
+	for i, operation := range operations {
+		if patch[i], err = decodeOperation(operation); err != nil {
+			return nil, &PatchError{Index: i, Operation: patch[i], Err: err}
+		}
+	}
+	return patch, nil
+}
+
+// decodeOperation converts a parsed operation, whose members may be
+// missing, which differs from null for value and from empty for path and from.
+func decodeOperation(doc interface{}) (Operation, error) {
+	var op Operation
+	members, ok := doc.(map[string]interface{})
+	if !ok {
+		return op, fmt.Errorf("%w: not an object", ErrInvalidPatch)
+	}
+	for _, field := range []struct {
+		name     string
+		dst      *string
+		required bool
+	}{
+		{"op", &op.Op, true},
+		{"path", &op.Path, true},
+		{"from", &op.From, members["op"] == OpMove || members["op"] == OpCopy},
+	} {
+		value, ok := members[field.name]
+		if !ok {
+			if field.required {
+				return op, fmt.Errorf("%w: missing %s", ErrInvalidPatch, field.name)
+			}
+			continue
+		}
+		if *field.dst, ok = value.(string); !ok {
+			return op, fmt.Errorf("%w: %s is not a string", ErrInvalidPatch, field.name)
+		}
+	}
+	if value, ok := members["value"]; ok {
+		data, err := Marshal(value)
+		if err != nil {
+			return op, err
+		}
+		op.Value = data
+	}
+	return op, nil
+}
+
+// ApplyPatch applies the JSON Patch patch to the JSON document doc:
+//
+//	patched, err := json.ApplyPatch(doc, []byte(`[{"op": "replace", "path": "/replicas", "value": 3}]`))
+//
+// The document is decoded with the unmarshal engine and encoded with the
+// marshal engine. Patches are applied atomically: on error, no document is
+// returned, and the error is a *PatchError for the failed operation.
+func ApplyPatch(doc, patch []byte) ([]byte, error) {
+	p, err := DecodePatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	return p.Apply(doc)
+}
+
+// ApplyPatchValue applies the JSON Patch patch to v, as encoded by the
+// marshal engine, and decodes the result into a new T with the unmarshal
+// engine. v is unchanged.
+func ApplyPatchValue[T any](v T, patch []byte) (T, error) {
+	var zero T
+	p, err := DecodePatch(patch)
+	if err != nil {
+		return zero, err
+	}
+	doc, err := toDocument(v)
+	if err != nil {
+		return zero, err
+	}
+	if doc, err = p.apply(doc); err != nil {
+		return zero, err
+	}
+	return fromDocument[T](doc)
+}
+
+// Apply applies the patch to the JSON document doc, see ApplyPatch.
+func (p Patch) Apply(doc []byte) ([]byte, error) {
+	parsed, err := parseDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	if parsed, err = p.apply(parsed); err != nil {
+		return nil, err
+	}
+	return Marshal(parsed)
+}
+
+func (p Patch) apply(doc interface{}) (interface{}, error) {
+	for i, op := range p {
+		var err error
+		if doc, err = op.apply(doc); err != nil {
+			return nil, &PatchError{Index: i, Operation: op, Err: err}
+		}
+	}
+	return doc, nil
+}
+
+func (op Operation) apply(doc interface{}) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	var value interface{}
+	switch op.Op {
+	case OpAdd, OpReplace, OpTest:
+		if op.Value == nil {
+			return nil, fmt.Errorf("%w: missing value", ErrInvalidPatch)
+		}
+		if value, err = parseDocument(op.Value); err != nil {
+			return nil, fmt.Errorf("%w: value: %v", ErrInvalidPatch, err)
+		}
+	case OpMove, OpCopy:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, fmt.Errorf("%w: from: %v", ErrInvalidPatch, err)
+		}
+		if value, err = getValue(doc, from); err != nil {
+			return nil, err
+		}
+		if op.Op == OpCopy {
+			value = copyDocument(value)
+			break
+		}
+		if path.hasPrefix(from) {
+			if len(path) == len(from) {
+				return doc, nil
+			}
+			return nil, fmt.Errorf("%w: cannot move %s into itself", ErrInvalidPatch, op.From)
+		}
+		if doc, err = removeValue(doc, from); err != nil {
+			return nil, err
+		}
+	}
+
+	switch op.Op {
+	case OpAdd, OpMove, OpCopy:
+		return addValue(doc, path, value)
+	case OpRemove:
+		return removeValue(doc, path)
+	case OpReplace:
+		if _, err := getValue(doc, path); err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		return updateParent(doc, path, func(parent interface{}, key string) (interface{}, error) {
+			return setChild(parent, key, value)
+		})
+	case OpTest:
+		current, err := getValue(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equalDocuments(current, value) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidPatch, op.Op)
+	}
+}
+
+// getValue returns the value at path in doc.
+func getValue(doc interface{}, path pointer) (interface{}, error) {
+	for i, token := range path {
+		child, err := getChild(doc, token)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, path[:i+1])
+		}
+		doc = child
+	}
+	return doc, nil
+}
+
+func getChild(node interface{}, key string) (interface{}, error) {
+	switch node := node.(type) {
+	case map[string]interface{}:
+		child, ok := node[key]
+		if !ok {
+			return nil, ErrPathNotFound
+		}
+		return child, nil
+	case []interface{}:
+		i, err := arrayIndex(key, len(node))
+		if err != nil {
+			return nil, err
+		}
+		return node[i], nil
+	default:
+		return nil, fmt.Errorf("%w: not an object or array", ErrPathNotFound)
+	}
+}
+
+// setChild replaces an existing child of node.
+func setChild(node interface{}, key string, value interface{}) (interface{}, error) {
+	switch node := node.(type) {
+	case map[string]interface{}:
+		node[key] = value
+		return node, nil
+	case []interface{}:
+		i, err := arrayIndex(key, len(node))
+		if err != nil {
+			return nil, err
+		}
+		node[i] = value
+		return node, nil
+	default:
+		return nil, fmt.Errorf("%w: not an object or array", ErrPathNotFound)
+	}
+}
+
+// updateParent replaces the parent of the value at path, which must not be
+// the root, with the result of update.
+func updateParent(doc interface{}, path pointer, update func(parent interface{}, key string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return update(doc, path[0])
+	}
+	child, err := getChild(doc, path[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, path[:1])
+	}
+	child, err = updateParent(child, path[1:], update)
+	if err != nil {
+		return nil, err
+	}
+	return setChild(doc, path[0], child)
+}
+
+func addValue(doc interface{}, path pointer, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return updateParent(doc, path, func(parent interface{}, key string) (interface{}, error) {
+		if array, ok := parent.([]interface{}); ok {
+			if key == "-" {
+				return append(array, value), nil
+			}
+			// Indexes up to the length are valid, the length appends.
+			i, err := arrayIndex(key, len(array)+1)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", err, path)
+			}
+			return slices.Insert(array, i, value), nil
+		}
+		if object, ok := parent.(map[string]interface{}); ok {
+			object[key] = value
+			return object, nil
+		}
+		return nil, fmt.Errorf("%w: %s: parent is not an object or array", ErrPathNotFound, path)
+	})
+}
+
+func removeValue(doc interface{}, path pointer) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w: cannot remove the root", ErrInvalidPatch)
+	}
+	return updateParent(doc, path, func(parent interface{}, key string) (interface{}, error) {
+		if _, err := getChild(parent, key); err != nil {
+			return nil, fmt.Errorf("%w: %s", err, path)
+		}
+		if array, ok := parent.([]interface{}); ok {
+			i, _ := strconv.Atoi(key)
+			return slices.Delete(array, i, i+1), nil
+		}
+		object := parent.(map[string]interface{})
+		delete(object, key)
+		return object, nil
+	})
+}
+
+// CreatePatch returns a JSON Patch turning the JSON document original into
+// modified. Objects are compared member by member and arrays element by
+// element; elements are added or removed at the end of arrays.
+func CreatePatch(original, modified []byte) (Patch, error) {
+	a, err := parseDocument(original)
+	if err != nil {
+		return nil, fmt.Errorf("json: original: %w", err)
+	}
+	b, err := parseDocument(modified)
+	if err != nil {
+		return nil, fmt.Errorf("json: modified: %w", err)
+	}
+	return createPatch(a, b)
+}
+
+// CreatePatchValues returns a JSON Patch turning original into modified,
+// as encoded by the marshal engine, see CreatePatch.
+func CreatePatchValues(original, modified interface{}) (Patch, error) {
+	a, err := toDocument(original)
+	if err != nil {
+		return nil, err
+	}
+	b, err := toDocument(modified)
+	if err != nil {
+		return nil, err
+	}
+	return createPatch(a, b)
+}
+
+func createPatch(original, modified interface{}) (Patch, error) {
+	patch := Patch{}
+	var err error
+	walkChanges(original, modified, pointer{}, func(change Change, path pointer) {
+		if err != nil {
+			return
+		}
+		op := Operation{Path: path.String()}
+		switch change.Type {
+		case ChangeAdded:
+			op.Op = OpAdd
+		case ChangeRemoved:
+			op.Op = OpRemove
+		case ChangeModified:
+			op.Op = OpReplace
+		}
+		if change.Type != ChangeRemoved {
+			if op.Value, err = Marshal(change.To); err != nil {
+				return
+			}
+		}
+		patch = append(patch, op)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return patch, nil
+}
+
+// sortedKeys returns the keys of object in sorted order.
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,334 @@
+package json
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApplyPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		// The examples of RFC 6902, appendix A.
+		{
+			name:  "add an object member",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz","value":"qux"}]`,
+			want:  `{"baz":"qux","foo":"bar"}`,
+		},
+		{
+			name:  "add an array element",
+			doc:   `{"foo":["bar","baz"]}`,
+			patch: `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			want:  `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			name:  "remove an object member",
+			doc:   `{"baz":"qux","foo":"bar"}`,
+			patch: `[{"op":"remove","path":"/baz"}]`,
+			want:  `{"foo":"bar"}`,
+		},
+		{
+			name:  "remove an array element",
+			doc:   `{"foo":["bar","qux","baz"]}`,
+			patch: `[{"op":"remove","path":"/foo/1"}]`,
+			want:  `{"foo":["bar","baz"]}`,
+		},
+		{
+			name:  "replace a value",
+			doc:   `{"baz":"qux","foo":"bar"}`,
+			patch: `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			want:  `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			name:  "move a value",
+			doc:   `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			want:  `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			name:  "move an array element",
+			doc:   `{"foo":["all","grass","cows","eat"]}`,
+			patch: `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			want:  `{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			name:  "test a value",
+			doc:   `{"baz":"qux","foo":["a",2,"c"]}`,
+			patch: `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`,
+			want:  `{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		{
+			name:    "test a value (error)",
+			doc:     `{"baz":"qux"}`,
+			patch:   `[{"op":"test","path":"/baz","value":"bar"}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			name:  "add a nested member object",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`,
+			want:  `{"child":{"grandchild":{}},"foo":"bar"}`,
+		},
+		{
+			name:    "add to a nonexistent target",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:  "escape ordering",
+			doc:   `{"/":9,"~1":10}`,
+			patch: `[{"op":"test","path":"/~01","value":10}]`,
+			want:  `{"/":9,"~1":10}`,
+		},
+		{
+			name:  "add an array value",
+			doc:   `{"foo":["bar"]}`,
+			patch: `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+			want:  `{"foo":["bar",["abc","def"]]}`,
+		},
+
+		{
+			name:  "replace the root",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"replace","path":"","value":[1]}]`,
+			want:  `[1]`,
+		},
+		{
+			name:  "add null",
+			doc:   `{}`,
+			patch: `[{"op":"add","path":"/a","value":null}]`,
+			want:  `{"a":null}`,
+		},
+		{
+			name:  "copy a value",
+			doc:   `{"a":{"b":[1]}}`,
+			patch: `[{"op":"copy","from":"/a","path":"/c"},{"op":"add","path":"/c/b/-","value":2}]`,
+			want:  `{"a":{"b":[1]},"c":{"b":[1,2]}}`,
+		},
+		{
+			name:  "big numbers are kept",
+			doc:   `{"n":12345678901234567890}`,
+			patch: `[{"op":"add","path":"/m","value":1.50}]`,
+			want:  `{"m":1.50,"n":12345678901234567890}`,
+		},
+		{
+			name:    "move into a child",
+			doc:     `{"a":{"b":1}}`,
+			patch:   `[{"op":"move","from":"/a","path":"/a/c"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "remove a missing member",
+			doc:     `{"a":1}`,
+			patch:   `[{"op":"remove","path":"/b"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:    "array index with a leading zero",
+			doc:     `[1,2]`,
+			patch:   `[{"op":"replace","path":"/01","value":3}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:    "array index out of range",
+			doc:     `[1,2]`,
+			patch:   `[{"op":"add","path":"/3","value":3}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:    "unknown operation",
+			doc:     `{}`,
+			patch:   `[{"op":"merge","path":"/a","value":1}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "missing value",
+			doc:     `{}`,
+			patch:   `[{"op":"add","path":"/a"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "missing from",
+			doc:     `{"a":1}`,
+			patch:   `[{"op":"copy","path":"/b"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "missing path",
+			doc:     `{}`,
+			patch:   `[{"op":"remove"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "not a patch",
+			doc:     `{}`,
+			patch:   `{"op":"remove","path":"/a"}`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "atomic",
+			doc:     `{"a":1}`,
+			patch:   `[{"op":"remove","path":"/a"},{"op":"test","path":"/a","value":1}]`,
+			wantErr: ErrPathNotFound,
+		},
+	}
+	for _, name := range engineNames {
+		t.Run(name, func(t *testing.T) {
+			restoreEngines(t)
+			engine := newTestEngine(t, name)
+			SetEngine(engine, engine)
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					got, err := ApplyPatch([]byte(tt.doc), []byte(tt.patch))
+					if tt.wantErr != nil {
+						if !errors.Is(err, tt.wantErr) {
+							t.Fatalf("ApplyPatch() error = %v, want %v", err, tt.wantErr)
+						}
+						return
+					}
+					if err != nil {
+						t.Fatalf("ApplyPatch() error = %v", err)
+					}
+					if string(got) != tt.want {
+						t.Errorf("ApplyPatch() = %s, want %s", got, tt.want)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestApplyPatch_Error(t *testing.T) {
+	_, err := ApplyPatch([]byte(`{"a":{"b":1}}`), []byte(`[{"op":"test","path":"/a/b","value":1},{"op":"remove","path":"/a/c"}]`))
+	var patchErr *PatchError
+	if !errors.As(err, &patchErr) {
+		t.Fatalf("ApplyPatch() error = %v, want a *PatchError", err)
+	}
+	if patchErr.Index != 1 || patchErr.Operation.Path != "/a/c" {
+		t.Errorf("PatchError = %+v, want operation 1 on /a/c", patchErr)
+	}
+	if want := "json: patch operation 1 (remove /a/c): path not found: /a/c"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	if _, err := ApplyPatch([]byte(`{"a":1} x`), []byte(`[]`)); err == nil {
+		t.Error("ApplyPatch() should reject trailing data in the document")
+	}
+}
+
+type patchTarget struct {
+	Name     string            `json:"name"`
+	Replicas int               `json:"replicas"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Ports    []int             `json:"ports"`
+}
+
+func TestApplyPatchValue(t *testing.T) {
+	original := patchTarget{Name: "api", Replicas: 2, Labels: map[string]string{"team": "core"}, Ports: []int{80}}
+	got, err := ApplyPatchValue(original, []byte(`[
+		{"op": "replace", "path": "/replicas", "value": 3},
+		{"op": "remove", "path": "/labels/team"},
+		{"op": "add", "path": "/ports/-", "value": 443}
+	]`))
+	if err != nil {
+		t.Fatalf("ApplyPatchValue() error = %v", err)
+	}
+	if got.Name != "api" || got.Replicas != 3 || len(got.Labels) != 0 || len(got.Ports) != 2 || got.Ports[1] != 443 {
+		t.Errorf("ApplyPatchValue() = %+v", got)
+	}
+	if original.Replicas != 2 || original.Labels["team"] != "core" || len(original.Ports) != 1 {
+		t.Errorf("ApplyPatchValue() changed the original: %+v", original)
+	}
+
+	if _, err := ApplyPatchValue(original, []byte(`[{"op":"replace","path":"/replicas","value":"three"}]`)); err == nil {
+		t.Error("ApplyPatchValue() should fail to decode a string into an int")
+	}
+}
+
+func TestCreatePatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		modified string
+		want     string
+	}{
+		{name: "equal", original: `{"a":[1,{"b":2}]}`, modified: `{"a":[1.0,{"b":2}]}`, want: `[]`},
+		{
+			name:     "object members",
+			original: `{"a":1,"b":{"c":"x","d":true},"e":null}`,
+			modified: `{"a":2,"b":{"c":"x"},"f":[]}`,
+			want:     `[{"op":"replace","path":"/a","value":2},{"op":"remove","path":"/b/d"},{"op":"remove","path":"/e"},{"op":"add","path":"/f","value":[]}]`,
+		},
+		{
+			name:     "array elements",
+			original: `[1,2,3,4]`,
+			modified: `[1,5]`,
+			want:     `[{"op":"replace","path":"/1","value":5},{"op":"remove","path":"/3"},{"op":"remove","path":"/2"}]`,
+		},
+		{
+			name:     "appended elements",
+			original: `{"a":[1]}`,
+			modified: `{"a":[1,2,3]}`,
+			want:     `[{"op":"add","path":"/a/1","value":2},{"op":"add","path":"/a/2","value":3}]`,
+		},
+		{
+			name:     "escaped keys",
+			original: `{}`,
+			modified: `{"a/b":{"~":1}}`,
+			want:     `[{"op":"add","path":"/a~1b","value":{"~":1}}]`,
+		},
+		{name: "type change", original: `{"a":[1]}`, modified: `{"a":{"0":1}}`, want: `[{"op":"replace","path":"/a","value":{"0":1}}]`},
+		{name: "root", original: `1`, modified: `"one"`, want: `[{"op":"replace","path":"","value":"one"}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := CreatePatch([]byte(tt.original), []byte(tt.modified))
+			if err != nil {
+				t.Fatalf("CreatePatch() error = %v", err)
+			}
+			got, err := Marshal(patch)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("CreatePatch() = %s, want %s", got, tt.want)
+			}
+
+			patched, err := patch.Apply([]byte(tt.original))
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if diff, _ := Diff(patched, []byte(tt.modified)); len(diff) != 0 {
+				t.Errorf("Apply() = %s, want %s", patched, tt.modified)
+			}
+		})
+	}
+
+	if _, err := CreatePatch([]byte(`{`), []byte(`{}`)); err == nil {
+		t.Error("CreatePatch() should reject invalid JSON")
+	}
+}
+
+func TestCreatePatchValues(t *testing.T) {
+	original := patchTarget{Name: "api", Replicas: 2, Ports: []int{80}}
+	modified := patchTarget{Name: "api", Replicas: 3, Labels: map[string]string{"team": "core"}, Ports: []int{80, 443}}
+	patch, err := CreatePatchValues(original, modified)
+	if err != nil {
+		t.Fatalf("CreatePatchValues() error = %v", err)
+	}
+	data, _ := Marshal(patch)
+	got, err := ApplyPatchValue(original, data)
+	if err != nil {
+		t.Fatalf("ApplyPatchValue() error = %v", err)
+	}
+	if changes, _ := DiffValues(got, modified); len(changes) != 0 {
+		t.Errorf("patched value differs from the modified value: %v", changes)
+	}
+}
@@ -0,0 +1,102 @@
+package json
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pointer is a parsed JSON Pointer (RFC 6901), e.g. /spec/containers/0.
+type pointer []string
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func parsePointer(s string) (pointer, error) {
+	if s == "" {
+		return pointer{}, nil
+	}
+	if s[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must be empty or start with /", s)
+	}
+	tokens := strings.Split(s[1:], "/")
+	for i, token := range tokens {
+		if strings.Contains(strings.ReplaceAll(strings.ReplaceAll(token, "~0", ""), "~1", ""), "~") {
+			return nil, fmt.Errorf("invalid JSON pointer %q: invalid escape in %q", s, token)
+		}
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func (p pointer) String() string {
+	var b strings.Builder
+	for _, token := range p {
+		b.WriteByte('/')
+		b.WriteString(pointerEscaper.Replace(token))
+	}
+	return b.String()
+}
+
+// append returns p extended with token, without sharing p's array.
+func (p pointer) append(token string) pointer {
+	return append(p[:len(p):len(p)], token)
+}
+
+// hasPrefix reports whether p is prefix or one of its descendants.
+func (p pointer) hasPrefix(prefix pointer) bool {
+	if len(p) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if p[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// readablePath formats the path of a value in doc, e.g.
+// spec.containers[0].image or labels["app.kubernetes.io/name"].
+// Array indexes are written in brackets, other keys are written after a dot
+// if they are identifiers and quoted in brackets otherwise.
+func readablePath(doc interface{}, p pointer) string {
+	var b strings.Builder
+	for _, token := range p {
+		switch node := doc.(type) {
+		case []interface{}:
+			b.WriteString("[" + token + "]")
+			doc = nil
+			if i, err := arrayIndex(token, len(node)); err == nil {
+				doc = node[i]
+			}
+		default:
+			if identifierPattern.MatchString(token) {
+				if b.Len() > 0 {
+					b.WriteByte('.')
+				}
+				b.WriteString(token)
+			} else {
+				b.WriteString("[" + strconv.Quote(token) + "]")
+			}
+			object, _ := node.(map[string]interface{})
+			doc = object[token]
+		}
+	}
+	return b.String()
+}
+
+// arrayIndex parses an array index of a pointer to an array of length n.
+// Indexes have no sign or leading zeros.
+func arrayIndex(token string, n int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrPathNotFound, token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i >= n {
+		return 0, fmt.Errorf("%w: array index %s out of range", ErrPathNotFound, token)
+	}
+	return i, nil
+}
@@ -0,0 +1,60 @@
+package json
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Factory creates an engine configured with opts. It returns an error
+// wrapping ErrUnsupportedOption for the options the engine cannot honor.
+type Factory func(opts Options) (Engine, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{
+		EngineStandard: newStdEngine,
+		EngineGoJson:   newGoJsonEngine,
+		EngineJsoniter: newJsoniterEngine,
+		EngineSonic:    newSonicEngine,
+	}
+)
+
+// Register makes an engine available by name to NewEngine, GetEngineByName,
+// WithEngine and SetEngineFromEnv, e.g. an adapter for another JSON library
+// or an instrumented wrapper. Call it from an init function. Run the
+// jsontest conformance suite to check the engine against encoding/json.
+//
+//	func init() {
+//		json.Register("segmentio", newSegmentioEngine)
+//	}
+//
+// Register panics if the name is empty or already registered, or if
+// factory is nil.
+func Register(name string, factory Factory) {
+	if name == "" {
+		panic("json: Register engine with an empty name")
+	}
+	if factory == nil {
+		panic(fmt.Sprintf("json: Register engine %s with a nil factory", name))
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("json: Register called twice for engine %s", name))
+	}
+	registry[name] = factory
+}
+
+// Engines returns the sorted names of the registered engines.
+func Engines() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,96 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/bytedance/sonic"
+)
+
+// sonicProbe covers the value kinds whose encoding must match encoding/json.
+var sonicProbe = map[string]interface{}{
+	"string": "<a & b> ",
+	"int":    -42,
+	"float":  3.14159,
+	"bool":   true,
+	"null":   nil,
+	"slice":  []interface{}{"x", 1.5, false},
+	"nested": map[string]interface{}{"z": 1, "a": 2},
+}
+
+// sonicAvailable reports whether sonic runs its own implementation on this
+// platform and Go runtime. sonic falls back to encoding/json on the platforms
+// and Go versions it does not support; it is also treated as unavailable if
+// it fails, or disagrees with encoding/json, on a probe value.
+var sonicAvailable = sync.OnceValue(func() (ok bool) {
+	if sonic.APIKind != sonic.UseSonicJSON {
+		return false
+	}
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	got, err := sonic.ConfigStd.Marshal(sonicProbe)
+	if err != nil {
+		return false
+	}
+	want, err := json.Marshal(sonicProbe)
+	return err == nil && bytes.Equal(got, want)
+})
+
+type sonicEngine struct {
+	api  sonic.API
+	opts Options
+}
+
+// NewSonicEngine returns the sonic engine, configured to produce the same
+// output as encoding/json. Where sonic is not available, it returns the
+// standard engine instead; see EngineName.
+func NewSonicEngine() Engine {
+	e, _ := newSonicEngine(DefaultOptions())
+	return e
+}
+
+// newSonicEngine creates the sonic engine, which supports all options. Where
+// sonic is not available, the options of the standard engine apply.
+func newSonicEngine(opts Options) (Engine, error) {
+	if !sonicAvailable() {
+		return newStdEngine(opts)
+	}
+	return &sonicEngine{api: sonic.Config{
+		EscapeHTML:            opts.EscapeHTML,
+		SortMapKeys:           opts.SortMapKeys,
+		CompactMarshaler:      true,
+		CopyString:            opts.CopyString,
+		ValidateString:        opts.ValidateString,
+		UseNumber:             opts.UseNumber,
+		DisallowUnknownFields: opts.DisallowUnknownFields,
+	}.Froze(), opts: opts}, nil
+}
+
+func (e *sonicEngine) Marshal(v interface{}) ([]byte, error) {
+	return e.api.Marshal(v)
+}
+
+func (e *sonicEngine) Unmarshal(data []byte, v interface{}) error {
+	return e.api.Unmarshal(data, v)
+}
+
+func (e *sonicEngine) MarshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
+	return e.api.MarshalIndent(v, prefix, indent)
+}
+
+func (e *sonicEngine) NewEncoder(w io.Writer) Encoder {
+	return e.api.NewEncoder(w)
+}
+
+// NewDecoder returns a decoder framing values with encoding/json and
+// decoding them with the engine. sonic's own stream decoder consumes the
+// whitespace after values, so its Buffered and InputOffset differ from
+// encoding/json, and it has no tokens.
+func (e *sonicEngine) NewDecoder(r io.Reader) Decoder {
+	return newStreamDecoder(r, e.opts, newSonicEngine)
+}
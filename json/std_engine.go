@@ -1,32 +1,85 @@
 package json
 
 import (
+	"bytes"
 	"encoding/json"
 	"io"
 )
 
-type stdEngine struct{}
+type stdEngine struct {
+	opts Options
+}
 
 func NewStdEngine() Engine {
-	return &stdEngine{}
+	e, _ := newStdEngine(DefaultOptions())
+	return e
+}
+
+// newStdEngine creates the standard engine. encoding/json always sorts map
+// keys, replaces invalid UTF-8 and copies strings: turning these options
+// off is not supported.
+func newStdEngine(opts Options) (Engine, error) {
+	switch {
+	case !opts.SortMapKeys:
+		return nil, unsupportedOption(EngineStandard, "unsorted map keys")
+	case !opts.ValidateString:
+		return nil, unsupportedOption(EngineStandard, "keeping invalid UTF-8")
+	case !opts.CopyString:
+		return nil, unsupportedOption(EngineStandard, "strings referencing the input")
+	}
+	return &stdEngine{opts: opts}, nil
 }
 
 func (e *stdEngine) Marshal(v interface{}) ([]byte, error) {
-	return json.Marshal(v)
+	if e.opts.EscapeHTML {
+		return json.Marshal(v)
+	}
+	return e.encode(v, "", "")
 }
 
 func (e *stdEngine) Unmarshal(data []byte, v interface{}) error {
-	return json.Unmarshal(data, v)
+	if !e.opts.UseNumber && !e.opts.DisallowUnknownFields {
+		return json.Unmarshal(data, v)
+	}
+	// Unmarshal reports syntax errors, trailing data included, before decoding.
+	if !json.Valid(data) {
+		return json.Unmarshal(data, v)
+	}
+	return e.NewDecoder(bytes.NewReader(data)).Decode(v)
 }
 
 func (e *stdEngine) MarshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
-	return json.MarshalIndent(v, prefix, indent)
+	if e.opts.EscapeHTML {
+		return json.MarshalIndent(v, prefix, indent)
+	}
+	return e.encode(v, prefix, indent)
+}
+
+// encode marshals v with an encoder, for the options only encoders support.
+func (e *stdEngine) encode(v interface{}, prefix, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(e.opts.EscapeHTML)
+	enc.SetIndent(prefix, indent)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
 }
 
 func (e *stdEngine) NewEncoder(w io.Writer) Encoder {
-	return json.NewEncoder(w)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(e.opts.EscapeHTML)
+	return enc
 }
 
 func (e *stdEngine) NewDecoder(r io.Reader) Decoder {
-	return json.NewDecoder(r)
+	dec := json.NewDecoder(r)
+	if e.opts.UseNumber {
+		dec.UseNumber()
+	}
+	if e.opts.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	return dec
 }
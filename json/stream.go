@@ -0,0 +1,217 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DecodeEach decodes the elements of the JSON array read from r one at a
+// time with the unmarshal engine, and calls fn with each. Only one element
+// is held in memory, so arrays larger than memory can be processed:
+//
+//	err := json.DecodeEach(file, func(r Record) error {
+//		return store.Save(ctx, r)
+//	})
+//
+// It stops at the first error, returned by fn or by decoding, which reports
+// the index of the element.
+func DecodeEach[T any](r io.Reader, fn func(T) error) error {
+	dec := NewDecoder(r)
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+	for index := 0; dec.More(); index++ {
+		var v T
+		if err := dec.Decode(&v); err != nil {
+			return fmt.Errorf("json: array element %d: %w", index, err)
+		}
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec Decoder, want Delim) error {
+	token, err := dec.Token()
+	if err == io.EOF {
+		return fmt.Errorf("json: expected %v: %w", want, io.ErrUnexpectedEOF)
+	}
+	if err != nil {
+		return err
+	}
+	if token != want {
+		return fmt.Errorf("json: expected %v, got %v", want, token)
+	}
+	return nil
+}
+
+// ArrayEncoder writes a JSON array element by element, with the marshal
+// engine, so that arrays larger than memory can be written:
+//
+//	enc := json.NewArrayEncoder(w)
+//	for rows.Next() {
+//		if err := enc.Encode(scan(rows)); err != nil {
+//			return err
+//		}
+//	}
+//	return enc.Close()
+//
+// Close writes the end of the array. ArrayEncoder is not safe for
+// concurrent use.
+type ArrayEncoder struct {
+	w      io.Writer
+	engine Engine
+	count  int
+	err    error
+}
+
+// NewArrayEncoder returns an encoder writing a JSON array to w.
+func NewArrayEncoder(w io.Writer) *ArrayEncoder {
+	return &ArrayEncoder{w: w, engine: MarshalEngine()}
+}
+
+// Encode writes v as the next element of the array. After a write error,
+// the array is left incomplete and Encode and Close return the error.
+// Encode and Close fail after Close.
+func (e *ArrayEncoder) Encode(v interface{}) error {
+	if e.err != nil {
+		return e.err
+	}
+	data, err := e.engine.Marshal(v)
+	if err != nil {
+		return err // nothing written, the array is still valid
+	}
+	separator := []byte{','}
+	if e.count == 0 {
+		separator[0] = '['
+	}
+	if _, e.err = e.w.Write(append(separator, data...)); e.err != nil {
+		return e.err
+	}
+	e.count++
+	return nil
+}
+
+// Count returns the number of elements written.
+func (e *ArrayEncoder) Count() int {
+	return e.count
+}
+
+// Close writes the end of the array, followed by a newline as Encoder does.
+// An array without elements is written as []. It does not close the writer.
+func (e *ArrayEncoder) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+	end := "]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	_, e.err = io.WriteString(e.w, end)
+	if e.err == nil {
+		e.err = errArrayClosed
+	}
+	return nil
+}
+
+var errArrayClosed = errors.New("json: ArrayEncoder is closed")
+
+// streamDecoder reads the input with encoding/json, which provides tokens,
+// and decodes the values with an engine, for the engines whose decoders do
+// not support the whole Decoder interface.
+type streamDecoder struct {
+	dec       *json.Decoder
+	opts      Options
+	newEngine Factory
+	engine    Engine
+}
+
+func newStreamDecoder(r io.Reader, opts Options, newEngine Factory) *streamDecoder {
+	dec := json.NewDecoder(r)
+	if opts.UseNumber {
+		dec.UseNumber()
+	}
+	return &streamDecoder{dec: dec, opts: opts, newEngine: newEngine}
+}
+
+func (d *streamDecoder) Decode(v interface{}) error {
+	var raw json.RawMessage
+	if err := d.dec.Decode(&raw); err != nil {
+		return err
+	}
+	if d.engine == nil {
+		engine, err := d.newEngine(d.opts)
+		if err != nil {
+			return err
+		}
+		d.engine = engine
+	}
+	return d.engine.Unmarshal(raw, v)
+}
+
+func (d *streamDecoder) Token() (Token, error) { return d.dec.Token() }
+func (d *streamDecoder) More() bool            { return d.dec.More() }
+func (d *streamDecoder) Buffered() io.Reader   { return d.dec.Buffered() }
+func (d *streamDecoder) InputOffset() int64    { return d.dec.InputOffset() }
+
+func (d *streamDecoder) UseNumber() {
+	d.dec.UseNumber()
+	d.opts.UseNumber = true
+	d.engine = nil
+}
+
+func (d *streamDecoder) DisallowUnknownFields() {
+	d.opts.DisallowUnknownFields = true
+	d.engine = nil
+}
+
+// marshalEncoder encodes values with an engine's Marshal and indents them
+// with encoding/json, for the engines whose encoders do not indent as
+// encoding/json does.
+type marshalEncoder struct {
+	w              io.Writer
+	opts           Options
+	newEngine      Factory
+	engine         Engine
+	prefix, indent string
+}
+
+func newMarshalEncoder(w io.Writer, opts Options, newEngine Factory) *marshalEncoder {
+	return &marshalEncoder{w: w, opts: opts, newEngine: newEngine}
+}
+
+func (e *marshalEncoder) Encode(v interface{}) error {
+	if e.engine == nil {
+		engine, err := e.newEngine(e.opts)
+		if err != nil {
+			return err
+		}
+		e.engine = engine
+	}
+	data, err := e.engine.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if e.prefix != "" || e.indent != "" {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, data, e.prefix, e.indent); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	}
+	_, err = e.w.Write(append(data, '\n'))
+	return err
+}
+
+func (e *marshalEncoder) SetIndent(prefix, indent string) {
+	e.prefix, e.indent = prefix, indent
+}
+
+func (e *marshalEncoder) SetEscapeHTML(on bool) {
+	e.opts.EscapeHTML = on
+	e.engine = nil
+}
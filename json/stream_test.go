@@ -0,0 +1,301 @@
+package json
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDecoder_Tokens(t *testing.T) {
+	input := `{"items": [{"id": 1}, {"id": 2}], "total": 2} {"next": null}`
+	for _, name := range engineNames {
+		t.Run(name, func(t *testing.T) {
+			dec := newTestEngine(t, name).NewDecoder(strings.NewReader(input))
+
+			var tokens []Token
+			for _, want := range []Token{Delim('{'), "items", Delim('[')} {
+				token, err := dec.Token()
+				if err != nil {
+					t.Fatalf("Token() error = %v", err)
+				}
+				if token != want {
+					t.Fatalf("Token() = %#v, want %#v", token, want)
+				}
+				tokens = append(tokens, token)
+			}
+
+			var ids []int
+			for dec.More() {
+				var item struct{ ID int }
+				if err := dec.Decode(&item); err != nil {
+					t.Fatalf("Decode() error = %v", err)
+				}
+				ids = append(ids, item.ID)
+			}
+			if fmt.Sprint(ids) != "[1 2]" {
+				t.Errorf("decoded ids = %v, want [1 2]", ids)
+			}
+
+			for _, want := range []Token{Delim(']'), "total", 2.0, Delim('}')} {
+				if token, err := dec.Token(); err != nil || token != want {
+					t.Fatalf("Token() = %#v, %v, want %#v", token, err, want)
+				}
+			}
+			if offset := dec.InputOffset(); offset != int64(strings.LastIndex(input, " {")) {
+				t.Errorf("InputOffset() = %d, want %d", offset, strings.LastIndex(input, " {"))
+			}
+
+			// The following value is decoded, not dropped.
+			var next map[string]interface{}
+			if err := dec.Decode(&next); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if v, ok := next["next"]; !ok || v != nil {
+				t.Errorf("Decode() = %v, want next: null", next)
+			}
+			if _, err := dec.Token(); err != io.EOF {
+				t.Errorf("Token() error = %v, want io.EOF", err)
+			}
+		})
+	}
+}
+
+func TestDecoder_Buffered(t *testing.T) {
+	for _, name := range engineNames {
+		t.Run(name, func(t *testing.T) {
+			dec := newTestEngine(t, name).NewDecoder(strings.NewReader(`{"a":1} trailer é`))
+			var v map[string]int
+			if err := dec.Decode(&v); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			rest, err := io.ReadAll(dec.Buffered())
+			if err != nil {
+				t.Fatalf("ReadAll(Buffered()) error = %v", err)
+			}
+			if string(rest) != " trailer é" {
+				t.Errorf("Buffered() = %q, want %q", rest, " trailer é")
+			}
+		})
+	}
+}
+
+func TestDecoder_Options(t *testing.T) {
+	type target struct {
+		N interface{}
+	}
+	for _, name := range engineNames {
+		t.Run(name, func(t *testing.T) {
+			engine := newTestEngine(t, name)
+
+			dec := engine.NewDecoder(strings.NewReader(`{"N": 12345678901234567890}`))
+			dec.UseNumber()
+			var got target
+			if err := dec.Decode(&got); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if n, ok := got.N.(Number); !ok || n.String() != "12345678901234567890" {
+				t.Errorf("Decode() N = %#v, want Number 12345678901234567890", got.N)
+			}
+
+			dec = engine.NewDecoder(strings.NewReader(`{"N": 1} {"N": 2, "extra": true}`))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&got); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if err := dec.Decode(&got); err == nil {
+				t.Error("Decode() should reject unknown field")
+			}
+		})
+	}
+}
+
+func TestEncoder_SetIndentAndEscapeHTML(t *testing.T) {
+	value := map[string]interface{}{"html": "<b>", "nested": map[string]int{"a": 1}}
+	for _, name := range engineNames {
+		t.Run(name, func(t *testing.T) {
+			engine := newTestEngine(t, name)
+
+			var got bytes.Buffer
+			enc := engine.NewEncoder(&got)
+			enc.SetIndent(">", "\t")
+			enc.SetEscapeHTML(false)
+			if err := enc.Encode(value); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			enc.SetIndent("", "")
+			enc.SetEscapeHTML(true)
+			if err := enc.Encode(value); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+
+			var want bytes.Buffer
+			stdEnc := stdjson.NewEncoder(&want)
+			stdEnc.SetIndent(">", "\t")
+			stdEnc.SetEscapeHTML(false)
+			_ = stdEnc.Encode(value)
+			stdEnc.SetIndent("", "")
+			stdEnc.SetEscapeHTML(true)
+			_ = stdEnc.Encode(value)
+
+			if got.String() != want.String() {
+				t.Errorf("Encode() = %q, want %q", got.String(), want.String())
+			}
+		})
+	}
+}
+
+func TestDecodeEach(t *testing.T) {
+	type record struct {
+		ID   int
+		Name string
+	}
+	const count = 10000
+	var input bytes.Buffer
+	input.WriteString(" [")
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			input.WriteString(",\n")
+		}
+		fmt.Fprintf(&input, `{"ID":%d,"Name":"record %d"}`, i, i)
+	}
+	input.WriteString("] ")
+
+	for _, name := range engineNames {
+		t.Run(name, func(t *testing.T) {
+			restoreEngines(t)
+			SetUnmarshalEngine(newTestEngine(t, name))
+
+			n := 0
+			err := DecodeEach(iotest.HalfReader(bytes.NewReader(input.Bytes())), func(r record) error {
+				if r.ID != n || r.Name != fmt.Sprintf("record %d", n) {
+					return fmt.Errorf("record %d = %+v", n, r)
+				}
+				n++
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("DecodeEach() error = %v", err)
+			}
+			if n != count {
+				t.Errorf("DecodeEach() called fn %d times, want %d", n, count)
+			}
+		})
+	}
+}
+
+func TestDecodeEach_Errors(t *testing.T) {
+	errStop := errors.New("stop")
+	tests := []struct {
+		name    string
+		input   string
+		fn      func(int) error
+		wantErr string
+	}{
+		{name: "empty array", input: `[]`},
+		{name: "not an array", input: `{"a":1}`, wantErr: "expected ["},
+		{name: "empty input", input: ``, wantErr: io.ErrUnexpectedEOF.Error()},
+		{name: "truncated", input: `[1,2`, wantErr: "array element 2"},
+		{name: "invalid element", input: `[1,"two",3]`, wantErr: "array element 1"},
+		{name: "fn error", input: `[1,2,3]`, fn: func(n int) error {
+			if n == 2 {
+				return errStop
+			}
+			return nil
+		}, wantErr: errStop.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := tt.fn
+			if fn == nil {
+				fn = func(int) error { return nil }
+			}
+			err := DecodeEach(strings.NewReader(tt.input), fn)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("DecodeEach() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("DecodeEach() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestArrayEncoder(t *testing.T) {
+	for _, name := range engineNames {
+		t.Run(name, func(t *testing.T) {
+			restoreEngines(t)
+			SetMarshalEngine(newTestEngine(t, name))
+
+			var buf bytes.Buffer
+			enc := NewArrayEncoder(&buf)
+			for i := 0; i < 3; i++ {
+				if err := enc.Encode(map[string]int{"id": i}); err != nil {
+					t.Fatalf("Encode() error = %v", err)
+				}
+			}
+			if err := enc.Encode(make(chan int)); err == nil {
+				t.Error("Encode(chan) should fail")
+			}
+			if err := enc.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if want := `[{"id":0},{"id":1},{"id":2}]` + "\n"; buf.String() != want {
+				t.Errorf("output = %q, want %q", buf.String(), want)
+			}
+			if enc.Count() != 3 {
+				t.Errorf("Count() = %d, want 3", enc.Count())
+			}
+			if err := enc.Encode(1); err == nil {
+				t.Error("Encode() after Close should fail")
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := NewArrayEncoder(&buf).Close(); err != nil || buf.String() != "[]\n" {
+		t.Errorf("empty array = %q, %v, want %q", buf.String(), err, "[]\n")
+	}
+
+	writeErr := errors.New("write failed")
+	enc := NewArrayEncoder(errWriter{writeErr})
+	if err := enc.Encode(1); !errors.Is(err, writeErr) {
+		t.Errorf("Encode() error = %v, want %v", err, writeErr)
+	}
+	if err := enc.Close(); !errors.Is(err, writeErr) {
+		t.Errorf("Close() error = %v, want %v", err, writeErr)
+	}
+}
+
+type errWriter struct{ err error }
+
+func (w errWriter) Write([]byte) (int, error) { return 0, w.err }
+
+func TestDecoder_TopLevelValues(t *testing.T) {
+	input := `{"one":1} 2 "three"` + "\n" + `{"four":4}`
+	want := []interface{}{map[string]interface{}{"one": 1.0}, 2.0, "three", map[string]interface{}{"four": 4.0}}
+	for _, name := range engineNames {
+		t.Run(name, func(t *testing.T) {
+			dec := newTestEngine(t, name).NewDecoder(strings.NewReader(input))
+			var got []interface{}
+			for dec.More() {
+				var v interface{}
+				if err := dec.Decode(&v); err != nil {
+					t.Fatalf("Decode() error = %v", err)
+				}
+				got = append(got, v)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("decoded %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package json
+
+import (
+	"bytes"
+	"io"
+	"unicode/utf8"
+)
+
+const replacementChar = "\uFFFD"
+
+// toValidUTF8 replaces every invalid byte of data with U+FFFD, as
+// encoding/json does in strings. Invalid bytes outside strings are syntax
+// errors either way.
+func toValidUTF8(data []byte) []byte {
+	if utf8.Valid(data) {
+		return data
+	}
+	valid := make([]byte, 0, len(data)+len(data)/2)
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		if r == utf8.RuneError && size == 1 {
+			valid = append(valid, replacementChar...)
+		} else {
+			valid = append(valid, data[:size]...)
+		}
+		data = data[size:]
+	}
+	return valid
+}
+
+// validUTF8Reader replaces invalid UTF-8 in a stream, see toValidUTF8.
+type validUTF8Reader struct {
+	r       io.Reader
+	buf     []byte
+	pending []byte // start of a rune split across reads
+	out     []byte
+	err     error
+}
+
+func newValidUTF8Reader(r io.Reader) *validUTF8Reader {
+	return &validUTF8Reader{r: r, buf: make([]byte, 4096)}
+}
+
+func (v *validUTF8Reader) Read(p []byte) (int, error) {
+	for len(v.out) == 0 {
+		if v.err != nil {
+			if len(v.pending) > 0 {
+				v.out, v.pending = toValidUTF8(v.pending), nil
+				break
+			}
+			return 0, v.err
+		}
+		n, err := v.r.Read(v.buf)
+		v.err = err
+		data := append(v.pending, v.buf[:n]...)
+		// Keep an incomplete rune at the end for the next read.
+		end := len(data)
+		for i := max(0, end-utf8.UTFMax+1); i < end; i++ {
+			if utf8.RuneStart(data[i]) {
+				if !utf8.FullRune(data[i:]) {
+					end = i
+				}
+				break
+			}
+		}
+		v.out = toValidUTF8(data[:end])
+		v.pending = append([]byte(nil), data[end:]...)
+	}
+	n := copy(p, v.out)
+	v.out = v.out[n:]
+	return n, nil
+}
+
+// buffered returns the data read from r but not from v yet.
+func (v *validUTF8Reader) buffered() io.Reader {
+	return io.MultiReader(bytes.NewReader(v.out), bytes.NewReader(v.pending))
+}
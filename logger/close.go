@@ -0,0 +1,139 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Buffering batches writes in memory and flushes them when the buffer is
+// full, every FlushInterval, on Sync and on Close. Entries above error level
+// (DPanic, Panic, Fatal) are synced right away.
+type Buffering struct {
+	Size          int           `mapstructure:"SIZE"`           // buffer size in bytes per output (default: 256 kB)
+	FlushInterval time.Duration `mapstructure:"FLUSH_INTERVAL"` // maximum time entries stay buffered (default: 30s)
+}
+
+const (
+	defaultBufferSize    = 256 * 1024
+	defaultFlushInterval = 30 * time.Second
+)
+
+func (b Buffering) setDefaults() Buffering {
+	if b.Size <= 0 {
+		b.Size = defaultBufferSize
+	}
+	if b.FlushInterval <= 0 {
+		b.FlushInterval = defaultFlushInterval
+	}
+	return b
+}
+
+// outputs collects the resources of a logger's outputs, released by Close.
+// It is shared by a logger and all of its children.
+type outputs struct {
+	mu      sync.Mutex
+	closers []func() error
+	closed  bool
+}
+
+func (o *outputs) add(closer func() error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.closers = append(o.closers, closer)
+}
+
+// close releases the resources once; later calls return nil.
+func (o *outputs) close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return nil
+	}
+	o.closed = true
+
+	var errs []error
+	for _, closer := range o.closers {
+		errs = append(errs, closer())
+	}
+	return errors.Join(errs...)
+}
+
+// bufferedWriteSyncer buffers writes until it is stopped, then writes
+// through, so that late entries of old child loggers are not stranded in
+// a buffer nobody flushes anymore.
+type bufferedWriteSyncer struct {
+	buffered *zapcore.BufferedWriteSyncer
+	ws       zapcore.WriteSyncer
+
+	mu      sync.RWMutex
+	stopped bool
+}
+
+func newBufferedWriteSyncer(ws zapcore.WriteSyncer, cfg Buffering, clock zapcore.Clock) *bufferedWriteSyncer {
+	return &bufferedWriteSyncer{
+		buffered: &zapcore.BufferedWriteSyncer{
+			WS:            ws,
+			Size:          cfg.Size,
+			FlushInterval: cfg.FlushInterval,
+			Clock:         clock,
+		},
+		ws: ws,
+	}
+}
+
+func (s *bufferedWriteSyncer) Write(p []byte) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stopped {
+		return s.ws.Write(p)
+	}
+	return s.buffered.Write(p)
+}
+
+func (s *bufferedWriteSyncer) Sync() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stopped {
+		return s.ws.Sync()
+	}
+	return s.buffered.Sync()
+}
+
+// stop flushes the buffer and stops the flush goroutine.
+// Writes in progress complete into the buffer before it is flushed.
+func (s *bufferedWriteSyncer) stop() error {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+	return s.buffered.Stop()
+}
+
+// consoleSyncer ignores the errors returned by Sync on terminals and pipes,
+// which do not support it.
+type consoleSyncer struct {
+	*os.File
+}
+
+func (c consoleSyncer) Sync() error {
+	err := c.File.Sync()
+	if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY) || errors.Is(err, syscall.EBADF) {
+		return nil
+	}
+	return err
+}
+
+// Close flushes and releases the outputs of l, such as files and buffers.
+// The outputs are shared with the children of l: close the root logger once,
+// on shutdown. Entries logged afterwards are written unbuffered.
+func (l *Logger) Close() error {
+	err := l.Sync()
+	if l.outputs == nil {
+		return err
+	}
+	return errors.Join(err, l.outputs.close())
+}
@@ -0,0 +1,236 @@
+package logger
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// tickClock is a clock whose tickers fire when Tick is called.
+type tickClock struct {
+	ticks chan time.Time
+}
+
+func newTickClock() *tickClock {
+	return &tickClock{ticks: make(chan time.Time)}
+}
+
+func (c *tickClock) Now() time.Time { return time.Now() }
+
+func (c *tickClock) NewTicker(time.Duration) *time.Ticker {
+	return &time.Ticker{C: c.ticks}
+}
+
+func (c *tickClock) Tick() {
+	c.ticks <- time.Now()
+}
+
+func newBufferedTestLogger(t *testing.T, out *TestOutput, opts ...Option) *Logger {
+	t.Helper()
+	opts = append([]Option{
+		WithLevel(LevelDebug),
+		WithDev(false),
+		WithSink(Sink{Format: FormatJSON, Output: OutputWriter, Writer: out}),
+		WithBuffering(0, time.Hour),
+	}, opts...)
+	logger, err := newLogger(opts...)
+	if err != nil {
+		t.Fatalf("newLogger() error = %v", err)
+	}
+	return logger
+}
+
+func TestLogger_CloseFlushesAllLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger, err := newLogger(
+		WithLevel(LevelDebug),
+		WithDev(false),
+		WithSink(Sink{Format: FormatJSON, Output: OutputFile, FilePath: path}),
+		WithBuffering(4096, time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("newLogger() error = %v", err)
+	}
+
+	const goroutines, lines = 8, 500
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			child := logger.With(zap.Int("goroutine", g))
+			for i := 0; i < lines; i++ {
+				child.Info("line", zap.Int("i", i))
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	count := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		count++
+	}
+	if count != goroutines*lines {
+		t.Errorf("file has %d lines, want %d", count, goroutines*lines)
+	}
+}
+
+func TestLogger_BufferingFlushInterval(t *testing.T) {
+	out := &TestOutput{}
+	clock := newTickClock()
+	logger := newBufferedTestLogger(t, out, WithClock(clock))
+	defer logger.Close()
+
+	logger.Info("buffered")
+	if out.String() != "" {
+		t.Fatalf("entry should be buffered, got %s", out.String())
+	}
+
+	clock.Tick()
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(out.String(), "buffered") {
+		if time.Now().After(deadline) {
+			t.Fatalf("entry should be flushed on tick, got %q", out.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLogger_BufferingSyncsAboveError(t *testing.T) {
+	out := &TestOutput{}
+	logger := newBufferedTestLogger(t, out)
+	defer logger.Close()
+
+	logger.Info("before panic")
+	func() {
+		defer func() { _ = recover() }()
+		logger.Panic("panic")
+	}()
+
+	if got := out.String(); !strings.Contains(got, "before panic") || !strings.Contains(got, `"msg":"panic"`) {
+		t.Errorf("entries should be synced on panic, got %q", got)
+	}
+}
+
+func TestLogger_CloseWritesThroughAfterwards(t *testing.T) {
+	out := &TestOutput{}
+	logger := newBufferedTestLogger(t, out)
+	child := logger.Named("worker")
+
+	logger.Info("before close")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("second Close() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "before close") {
+		t.Fatalf("Close should flush, got %q", out.String())
+	}
+
+	child.Info("after close")
+	if !strings.Contains(out.String(), "after close") {
+		t.Errorf("entries after Close should be written directly, got %q", out.String())
+	}
+}
+
+func TestInit_SyncsPreviousLogger(t *testing.T) {
+	resetGlobal(t)
+	first := &TestOutput{}
+	second := &TestOutput{}
+	defer func() { _ = Close() }()
+
+	Init(WithDev(false), WithSink(Sink{Format: FormatJSON, Output: OutputWriter, Writer: first}), WithBuffering(0, time.Hour))
+	previous := GetLogger()
+	defer func() { _ = previous.Close() }()
+	Info("from first")
+	if first.String() != "" {
+		t.Fatalf("entry should be buffered, got %q", first.String())
+	}
+
+	Init(WithDev(false), WithSink(Sink{Format: FormatJSON, Output: OutputWriter, Writer: second}), WithBuffering(0, time.Hour))
+	if !strings.Contains(first.String(), "from first") {
+		t.Errorf("re-Init should flush the previous logger, got %q", first.String())
+	}
+
+	Info("from second")
+	if err := Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if !strings.Contains(second.String(), "from second") || strings.Contains(first.String(), "from second") {
+		t.Errorf("entries should go to the new logger only: first=%q second=%q", first.String(), second.String())
+	}
+}
+
+func TestInit_HeldChildAcrossReInit(t *testing.T) {
+	resetGlobal(t)
+	out := &TestOutput{}
+
+	Init(WithDev(false), WithSink(Sink{Format: FormatJSON, Output: OutputWriter, Writer: out}), WithBuffering(0, time.Hour))
+	first := GetLogger()
+	child := Named("worker")
+
+	const writers, entries = 4, 100
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < entries; j++ {
+				child.Info("held")
+				Info("global")
+			}
+		}()
+	}
+	var replaced []*Logger
+	for i := 0; i < 5; i++ {
+		Init(WithDev(false), WithSink(Sink{Format: FormatJSON, Output: OutputWriter, Writer: &TestOutput{}}))
+		replaced = append(replaced, GetLogger())
+	}
+	wg.Wait()
+	for _, logger := range replaced {
+		_ = logger.Close()
+	}
+
+	// Init left the first logger open: the child still writes to its buffer.
+	child.Info("after re-init")
+	if strings.Contains(out.String(), "after re-init") {
+		t.Fatal("the first logger should still buffer its entries")
+	}
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got := strings.Count(out.String(), `"msg":"held"`); got != writers*entries {
+		t.Errorf("got %d held entries, want %d", got, writers*entries)
+	}
+	if !strings.Contains(out.String(), "after re-init") {
+		t.Error("Close should flush the child's entries")
+	}
+}
+
+func TestLogger_CloseStdout(t *testing.T) {
+	logger, err := newLogger(WithFormat(FormatJSON))
+	if err != nil {
+		t.Fatalf("newLogger() error = %v", err)
+	}
+	// Syncing a terminal or pipe is not an error worth reporting on shutdown.
+	if err := logger.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
@@ -1,28 +1,84 @@
 package logger
 
 import (
+	"errors"
 	"fmt"
+	"github.com/laziness-coders/go-utils/configs"
 	"github.com/laziness-coders/go-utils/generic"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 )
 
 // Config defines the logger configuration.
+// It can be decoded by configs.ConfigLoader, e.g. as a LOGGER section, and
+// passed to InitFromConfig.
 type Config struct {
-	Level      string // debug, info, warn, error (default: info)
-	Format     string // json, text, file (default: json)
-	FilePath   string // file or directory path (only for file format)
-	MaxSize    int    // max size in MB before rotation (default: 100)
-	MaxAge     int    // max days to retain old logs (default: 30)
-	MaxBackups int    // max number of old log files (default: 3)
-	Compress   bool   // compress rotated files (default: false)
-	IsDev      *bool  // use zap's development config for human-readable output (default: false)
-	CallerSkip int    // caller skip for accurate logging (default: 1)
-
-	AtomicLevel zap.AtomicLevel // atomic level for dynamic level changes
+	Level      string `mapstructure:"LEVEL"`       // debug, info, warn, error, or a spec like "info,payments=debug" (default: info)
+	Format     string `mapstructure:"FORMAT"`      // json, text, logfmt, pretty, file (default: text)
+	FilePath   string `mapstructure:"FILE_PATH"`   // file or directory path (only for file format)
+	MaxSize    int    `mapstructure:"MAX_SIZE"`    // max size in MB before rotation (default: 100)
+	MaxAge     int    `mapstructure:"MAX_AGE"`     // max days to retain old logs (default: 30)
+	MaxBackups int    `mapstructure:"MAX_BACKUPS"` // max number of old log files (default: 3)
+	Compress   bool   `mapstructure:"COMPRESS"`    // compress rotated files (default: false)
+	IsDev      *bool  `mapstructure:"DEV"`         // enable zap's development mode, e.g. DPanic panics; the format is unchanged (default: true)
+	CallerSkip int    `mapstructure:"CALLER_SKIP"` // caller skip for accurate logging (default: 1)
+
+	// Rotation rotates file outputs by time and size, with file names from a
+	// pattern and separate files per level. Nil rotates by size only.
+	Rotation *Rotation `mapstructure:"ROTATION"`
+
+	// Sinks lists the outputs to write to. Unless it has other than otel
+	// sinks, a single output is also derived from Format and FilePath.
+	Sinks []Sink `mapstructure:"-"`
+
+	// Redaction masks sensitive fields and values before they are written.
+	// Nil disables redaction.
+	Redaction *Redaction `mapstructure:"-"`
+
+	// Sampling enables zap's sampler to cap repeated entries. Nil disables it.
+	Sampling *Sampling `mapstructure:"SAMPLING"`
+	// RateLimit caps entries per message and reports the suppressed ones.
+	// Nil disables it.
+	RateLimit *RateLimit `mapstructure:"RATE_LIMIT"`
+	// Buffering batches writes to the outputs. Nil writes every entry directly.
+	Buffering *Buffering `mapstructure:"BUFFERING"`
+
+	// Clock provides entry timestamps, rate limit windows, buffer flushes and
+	// rotation times (default: system clock).
+	Clock zapcore.Clock `mapstructure:"-"`
+
+	// TraceCorrelation selects the trace fields logged by Trace and Ctx
+	// (default: OpenTelemetry trace_id and span_id).
+	TraceCorrelation TraceCorrelation `mapstructure:"TRACE"`
+
+	// LevelOverrides sets the level of Named loggers, keyed by logger name.
+	// Names are hierarchical: "payments" also applies to "payments.stripe".
+	LevelOverrides map[string]string `mapstructure:"LEVEL_OVERRIDES"`
+
+	AtomicLevel zap.AtomicLevel `mapstructure:"-"` // atomic level for dynamic level changes
+}
+
+// ConfigFromApp returns a Config with the level and file path of the
+// application config. Entries are written to AppLogPath if set, else to the
+// console, in the pretty format in the dev environment.
+func ConfigFromApp(app configs.AppConfig) Config {
+	cfg := Config{
+		Level: app.AppLogLevel,
+		IsDev: generic.ToPointer(app.AppEnv.IsDevelopment()),
+	}
+	switch {
+	case app.AppLogPath != "":
+		cfg.Format = FormatFile
+		cfg.FilePath = app.AppLogPath
+	case app.AppEnv.IsDevelopment():
+		cfg.Format = FormatPretty
+	}
+	return cfg
 }
 
 // newConfig creates a new Config with the given options applied.
@@ -31,13 +87,38 @@ func newConfig(opts ...Option) (*Config, error) {
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	specErr := cfg.applyLevelSpec()
+	if specErr != nil {
+		// Reported once, with the other invalid fields.
+		cfg.Level = ""
+	}
 	cfg = cfg.setDefaults().setAtomicLevel()
-	if err := cfg.validate(); err != nil {
+	if err := errors.Join(specErr, cfg.validate()); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 	return cfg, nil
 }
 
+// applyLevelSpec splits a Level like "info,payments=debug" into the base
+// Level and LevelOverrides. Overrides from the spec win over existing ones.
+func (c *Config) applyLevelSpec() error {
+	if !strings.ContainsAny(c.Level, ",=") {
+		return nil
+	}
+	base, overrides, err := parseLevelSpec(c.Level)
+	if err != nil {
+		return err
+	}
+	c.Level = base
+	if c.LevelOverrides == nil {
+		c.LevelOverrides = make(map[string]string, len(overrides))
+	}
+	for name, level := range overrides {
+		c.LevelOverrides[name] = level
+	}
+	return nil
+}
+
 // setDefaults applies default values to the configuration.
 func (c *Config) setDefaults() *Config {
 	if c.Level == "" {
@@ -64,124 +145,183 @@ func (c *Config) setDefaults() *Config {
 		c.IsDev = generic.ToPointer(true)
 	}
 
+	for i := range c.Sinks {
+		c.Sinks[i] = c.Sinks[i].setDefaults(*c)
+	}
+
+	if c.Sampling != nil {
+		c.Sampling = generic.ToPointer(c.Sampling.setDefaults())
+	}
+	if c.RateLimit != nil {
+		c.RateLimit = generic.ToPointer(c.RateLimit.setDefaults())
+	}
+	if c.Buffering != nil {
+		c.Buffering = generic.ToPointer(c.Buffering.setDefaults())
+	}
+	if c.Clock == nil {
+		c.Clock = zapcore.DefaultClock
+	}
+
 	return c
 }
 
 func (c *Config) setAtomicLevel() *Config {
-	var level zapcore.Level
-	switch c.Level {
-	case LevelDebug:
-		level = zapcore.DebugLevel
-	case LevelInfo:
-		level = zapcore.InfoLevel
-	case LevelWarn:
-		level = zapcore.WarnLevel
-	case LevelError:
-		level = zapcore.ErrorLevel
-	default:
-		level = zapcore.InfoLevel
-	}
+	// Invalid levels are reported by validate; fall back to info meanwhile.
+	level, _ := parseLevel(c.Level)
 	c.AtomicLevel = zap.NewAtomicLevelAt(level)
 	return c
 }
 
-// validate checks if the configuration is valid.
+// validate checks if the configuration is valid, reporting all invalid
+// fields at once.
 func (c Config) validate() error {
+	var errs []error
+
 	// validate level
-	switch c.Level {
-	case LevelDebug, LevelInfo, LevelWarn, LevelError:
-		// valid
-	default:
-		return fmt.Errorf("invalid log level: %s", c.Level)
+	if _, err := parseLevel(c.Level); err != nil {
+		errs = append(errs, err)
+	}
+	names := make([]string, 0, len(c.LevelOverrides))
+	for name := range c.LevelOverrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := parseLevel(c.LevelOverrides[name]); err != nil {
+			errs = append(errs, fmt.Errorf("logger %s: %w", name, err))
+		}
 	}
 
 	// validate format
 	switch c.Format {
-	case FormatJSON, FormatText, FormatFile:
+	case FormatJSON, FormatText, FormatLogfmt, FormatPretty, FormatFile:
 		// valid
 	default:
-		return fmt.Errorf("invalid log format: %s", c.Format)
+		errs = append(errs, fmt.Errorf("invalid log format: %s", c.Format))
 	}
 
 	// If format is file, FilePath is required
 	if c.Format == FormatFile && c.FilePath == "" {
-		return fmt.Errorf("file path is required for file format")
+		errs = append(errs, fmt.Errorf("file path is required for file format"))
 	}
 
 	// If FilePath is provided, ensure the directory exists or can be created
 	if c.FilePath != "" {
 		dir := filepath.Dir(c.FilePath)
 		if err := os.MkdirAll(dir, logDirPermission); err != nil {
-			return fmt.Errorf("failed to create log directory %s: %w", dir, err)
+			errs = append(errs, fmt.Errorf("failed to create log directory %s: %w", dir, err))
 		}
 	}
 
-	return nil
+	if c.Rotation != nil {
+		if err := c.Rotation.setDefaults(c.legacySink()).validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := c.TraceCorrelation.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	for i, sink := range c.Sinks {
+		if err := sink.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("sink %d: %w", i, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// withoutLevels returns the configuration without the settings that
+// InitFromConfig can change in place.
+func (c Config) withoutLevels() Config {
+	c.Level = ""
+	c.LevelOverrides = nil
+	c.AtomicLevel = zap.AtomicLevel{}
+	return c
 }
 
 func (c Config) IsDevelopment() bool {
 	return c.IsDev != nil && *c.IsDev
 }
 
-func (c Config) buildZapEncoder() zapcore.Encoder {
-	// Create encoder config
-	var encoderConfig zapcore.EncoderConfig
+// buildZapEncoder creates the encoder for the given format, redacting if
+// configured. Color only applies to the pretty format.
+func (c Config) buildZapEncoder(format string, color bool) zapcore.Encoder {
 	var encoder zapcore.Encoder
 
-	// Use zap's development config when IsDev is true
-	if c.IsDevelopment() {
-		// Use zap's development config
-		encoderConfig = zap.NewDevelopmentEncoderConfig()
-		encoder = zapcore.NewConsoleEncoder(encoderConfig)
-		return encoder
+	switch format {
+	case FormatText:
+		encoder = zapcore.NewConsoleEncoder(productionEncoderConfig())
+	case FormatLogfmt:
+		encoder = newLogfmtEncoder(logfmtEncoderConfig())
+	case FormatPretty:
+		encoder = newPrettyEncoder(color)
+	default:
+		encoder = zapcore.NewJSONEncoder(productionEncoderConfig())
 	}
 
-	// Use production config for other cases
-	encoderConfig = zap.NewProductionEncoderConfig()
-	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
-
-	// Create encoder based on format
-	switch c.Format {
-	case FormatJSON, FormatFile:
-		encoder = zapcore.NewJSONEncoder(encoderConfig)
-	case FormatText:
-		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	if c.Redaction != nil {
+		encoder = newRedactEncoder(encoder, newRedactor(*c.Redaction))
 	}
 	return encoder
 }
 
-func (c Config) buildZapWriteSyncer() zapcore.WriteSyncer {
-	// Create writer sync based on format
-	var writeSyncer zapcore.WriteSyncer
-	switch c.Format {
-	case FormatFile:
-		writeSyncer = createFileWriter(c)
-	default:
-		writeSyncer = zapcore.AddSync(os.Stdout)
+// productionEncoderConfig is zap's production configuration with ISO8601
+// times and uppercase levels, used by the json and text formats.
+func productionEncoderConfig() zapcore.EncoderConfig {
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.EncodeTime = zapcore.ISO8601TimeEncoder
+	cfg.EncodeLevel = zapcore.CapitalLevelEncoder
+	return cfg
+}
+
+// buildZapCore creates a core for every sink and tees them together.
+// OTel sinks come in addition to the output of Format and FilePath: it is
+// only replaced by other sinks.
+func (c Config) buildZapCore(res *outputs) zapcore.Core {
+	outputs := 0
+	for _, sink := range c.Sinks {
+		if sink.Output != OutputOTel {
+			outputs++
+		}
+	}
+
+	cores := make([]zapcore.Core, 0, len(c.Sinks)+1)
+	if outputs == 0 {
+		cores = append(cores, c.legacySink().setDefaults(c).buildCore(c, res))
 	}
-	return writeSyncer
+	for _, sink := range c.Sinks {
+		cores = append(cores, sink.buildCore(c, res))
+	}
+	return zapcore.NewTee(cores...)
 }
 
-func (c Config) buildZapLogger() *zap.Logger {
-	core := zapcore.NewCore(
-		c.buildZapEncoder(),
-		c.buildZapWriteSyncer(),
-		c.AtomicLevel,
-	)
+// buildZapLogger creates the zap logger and the outputs it writes to.
+func (c Config) buildZapLogger(level *levelControl) (*zap.Logger, *outputs) {
+	res := &outputs{}
+	core := c.buildZapCore(res)
+	if c.RateLimit != nil {
+		core = newRateLimitCore(core, *c.RateLimit, c.Clock)
+	}
+	if c.Sampling != nil {
+		core = zapcore.NewSamplerWithOptions(core, c.Sampling.Tick, c.Sampling.Initial, c.Sampling.Thereafter)
+	}
+	// The level core applies the (per-name) levels, sinks only their own threshold.
+	core = newLevelCore(core, level)
 
-	// Use zap's development options when IsDev is true
 	var (
 		zapLogger *zap.Logger
 		zapOpts   = []zap.Option{
 			zap.AddCaller(),
 			zap.AddCallerSkip(c.CallerSkip),
 			zap.AddStacktrace(zapcore.ErrorLevel),
+			zap.WithClock(c.Clock),
 		}
 	)
 
+	// IsDev enables zap's development mode only: the encoders follow Format.
 	if c.IsDevelopment() {
-		// Enable development options
 		zapOpts = append(zapOpts, zap.Development())
 	}
 
@@ -189,13 +329,13 @@ func (c Config) buildZapLogger() *zap.Logger {
 		core,
 		zapOpts...,
 	)
-	return zapLogger
+	return zapLogger, res
 }
 
 // createFileWriter creates a file writer with rotation support.
-func createFileWriter(cfg Config) zapcore.WriteSyncer {
+func createFileWriter(sink Sink) *lumberjack.Logger {
 	// If FilePath is a directory, create a default filename
-	filePath := cfg.FilePath
+	filePath := sink.FilePath
 	if stat, err := os.Stat(filePath); err == nil && stat.IsDir() {
 		filePath = filepath.Join(filePath, "app.log")
 	}
@@ -203,11 +343,11 @@ func createFileWriter(cfg Config) zapcore.WriteSyncer {
 	// Create lumberjack logger for rotation
 	lumberjackLogger := &lumberjack.Logger{
 		Filename:   filePath,
-		MaxSize:    cfg.MaxSize,
-		MaxAge:     cfg.MaxAge,
-		MaxBackups: cfg.MaxBackups,
-		Compress:   cfg.Compress,
+		MaxSize:    sink.MaxSize,
+		MaxAge:     sink.MaxAge,
+		MaxBackups: sink.MaxBackups,
+		Compress:   sink.compress(),
 	}
 
-	return zapcore.AddSync(lumberjackLogger)
+	return lumberjackLogger
 }
@@ -0,0 +1,193 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/laziness-coders/go-utils/configs"
+	"github.com/laziness-coders/go-utils/generic"
+)
+
+func TestConfig_Decode(t *testing.T) {
+	dir := t.TempDir()
+	content := `
+LOGGER:
+  LEVEL: "info,payments=debug"
+  FORMAT: file
+  FILE_PATH: /var/log/svc.log
+  MAX_AGE: 14
+  COMPRESS: true
+  DEV: false
+  ROTATION:
+    INTERVAL: 24h
+    PATTERN: "{service}-{date}.log"
+    LEVELS:
+      error: error.log
+  RATE_LIMIT:
+    WINDOW: 2s
+    BURST: 5
+  TRACE:
+    FORMATS: [otel, datadog]
+  LEVEL_OVERRIDES:
+    db: warn
+`
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Logger Config `mapstructure:"LOGGER"`
+	}
+	if err := configs.New(&cfg).Load(configs.AppEnvironmentTest, dir); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	got := cfg.Logger
+	if got.Level != "info,payments=debug" || got.Format != FormatFile || got.FilePath != "/var/log/svc.log" {
+		t.Errorf("Level, Format, FilePath = %q, %q, %q", got.Level, got.Format, got.FilePath)
+	}
+	if got.MaxAge != 14 || !got.Compress {
+		t.Errorf("MaxAge, Compress = %d, %v, want 14, true", got.MaxAge, got.Compress)
+	}
+	if got.IsDev == nil || *got.IsDev {
+		t.Errorf("IsDev = %v, want false", got.IsDev)
+	}
+	if got.Rotation == nil || got.Rotation.Interval != 24*time.Hour || got.Rotation.Levels["error"] != "error.log" {
+		t.Errorf("Rotation = %+v, want daily with an error file", got.Rotation)
+	}
+	if got.RateLimit == nil || got.RateLimit.Window != 2*time.Second || got.RateLimit.Burst != 5 {
+		t.Errorf("RateLimit = %+v, want 2s and 5", got.RateLimit)
+	}
+	if strings.Join(got.TraceCorrelation.Formats, ",") != "otel,datadog" {
+		t.Errorf("TraceCorrelation.Formats = %v", got.TraceCorrelation.Formats)
+	}
+	if got.LevelOverrides["db"] != LevelWarn {
+		t.Errorf("LevelOverrides = %v, want db=warn", got.LevelOverrides)
+	}
+}
+
+func TestNewConfig_ReportsAllErrors(t *testing.T) {
+	_, err := newConfig(WithConfig(Config{
+		Level:            "loud",
+		Format:           "xml",
+		LevelOverrides:   map[string]string{"db": "quiet"},
+		Rotation:         &Rotation{Interval: -time.Hour, Pattern: "{host}.log"},
+		TraceCorrelation: TraceCorrelation{Formats: []string{"zipkin"}},
+		Sinks:            []Sink{{Format: "xml", Output: OutputWriter}},
+	}))
+	if err == nil {
+		t.Fatal("newConfig() error = nil, want an error")
+	}
+
+	for _, want := range []string{
+		"invalid log level: loud",
+		"logger db: invalid log level: quiet",
+		"invalid log format: xml",
+		"invalid rotation interval: -1h0m0s",
+		"unknown placeholder {host}",
+		"invalid trace format: zipkin",
+		"sink 0: invalid sink format: xml",
+		"writer is required for writer output",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not report %q", err, want)
+		}
+	}
+}
+
+func TestWithConfig_KeepsCallerValues(t *testing.T) {
+	cfg := Config{
+		Level:          "info,payments=debug",
+		LevelOverrides: map[string]string{"db": LevelWarn},
+		Sinks:          []Sink{{Output: OutputWriter, Writer: &bytes.Buffer{}}},
+	}
+	if _, err := newConfig(WithConfig(cfg)); err != nil {
+		t.Fatalf("newConfig() error = %v", err)
+	}
+	if len(cfg.LevelOverrides) != 1 || cfg.Sinks[0].Format != "" {
+		t.Errorf("WithConfig modified its argument: %+v", cfg)
+	}
+}
+
+func TestInitFromConfig(t *testing.T) {
+	resetGlobal(t)
+	out := &bytes.Buffer{}
+	cfg := Config{
+		Level: LevelInfo,
+		IsDev: generic.ToPointer(false),
+		Sinks: []Sink{{Format: FormatJSON, Output: OutputWriter, Writer: out}},
+	}
+
+	if err := InitFromConfig(cfg); err != nil {
+		t.Fatalf("InitFromConfig() error = %v", err)
+	}
+	first := GetLogger()
+	Debug("dropped")
+
+	// A level change is applied in place.
+	cfg.Level = "debug,db=error"
+	if err := InitFromConfig(cfg); err != nil {
+		t.Fatalf("InitFromConfig() error = %v", err)
+	}
+	if GetLogger() != first {
+		t.Fatal("a level change should not replace the logger")
+	}
+	Debug("kept")
+	Named("db").Warn("dropped by override")
+	if Level() != LevelDebug {
+		t.Errorf("Level() = %s, want debug", Level())
+	}
+	if got := strings.Count(out.String(), "\n"); got != 1 || !strings.Contains(out.String(), `"msg":"kept"`) {
+		t.Errorf("output = %q, want only the kept entry", out.String())
+	}
+
+	// Other changes rebuild the logger.
+	cfg.Sinks = []Sink{{Format: FormatText, Output: OutputWriter, Writer: out}}
+	if err := InitFromConfig(cfg); err != nil {
+		t.Fatalf("InitFromConfig() error = %v", err)
+	}
+	if GetLogger() == first {
+		t.Error("an output change should replace the logger")
+	}
+
+	// Invalid configs leave the logger in place.
+	current := GetLogger()
+	if err := InitFromConfig(Config{Level: "loud"}); err == nil {
+		t.Error("InitFromConfig() with an invalid level should fail")
+	}
+	if GetLogger() != current {
+		t.Error("an invalid config should not replace the logger")
+	}
+}
+
+func TestConfigFromApp(t *testing.T) {
+	tests := []struct {
+		name string
+		app  configs.AppConfig
+		want Config
+	}{
+		{
+			name: "dev console",
+			app:  configs.AppConfig{AppEnv: configs.AppEnvironmentDev, AppLogLevel: LevelDebug},
+			want: Config{Level: LevelDebug, Format: FormatPretty, IsDev: generic.ToPointer(true)},
+		},
+		{
+			name: "prod file",
+			app:  configs.AppConfig{AppEnv: configs.AppEnvironmentProd, AppLogLevel: LevelWarn, AppLogPath: "/var/log/app"},
+			want: Config{Level: LevelWarn, Format: FormatFile, FilePath: "/var/log/app", IsDev: generic.ToPointer(false)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ConfigFromApp(tt.app)
+			if got.Level != tt.want.Level || got.Format != tt.want.Format || got.FilePath != tt.want.FilePath ||
+				*got.IsDev != *tt.want.IsDev {
+				t.Errorf("ConfigFromApp() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
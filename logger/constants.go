@@ -2,9 +2,21 @@ package logger
 
 // Log format constants
 const (
-	FormatJSON = "json"
-	FormatText = "text"
-	FormatFile = "file"
+	FormatJSON   = "json"
+	FormatText   = "text"
+	FormatLogfmt = "logfmt"
+	FormatPretty = "pretty" // colorized, for development
+	FormatFile   = "file"
+)
+
+// Sink output constants
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+	OutputFile   = "file"
+	OutputWriter = "writer"
+	OutputOTel   = "otel"
+	OutputCore   = "core"
 )
 
 // Log level constants
@@ -2,28 +2,148 @@ package logger
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 
-	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
-// ExtractTraceFields extracts tracing information from context for both Datadog and OpenTelemetry.
+// ContextExtractor returns fields to log from a context, e.g. a tenant ID set
+// by an HTTP middleware. It must return nil when the context has none.
+type ContextExtractor func(ctx context.Context) []zap.Field
+
+type contextFieldsKey struct{}
+
+type contextLoggerKey struct{}
+
+type namedExtractor struct {
+	name      string
+	extractor ContextExtractor
+}
+
+var (
+	// extractors is an immutable snapshot, replaced on every registration.
+	extractors   atomic.Pointer[[]namedExtractor]
+	extractorsMu sync.Mutex
+)
+
+// ExtractTraceFields extracts the OpenTelemetry trace_id and span_id from context.
+// Use ExtractTraceFieldsWith, or the WithTraceCorrelation option, for other
+// formats such as Datadog or GCP.
 func ExtractTraceFields(ctx context.Context) []zap.Field {
+	return ExtractTraceFieldsWith(ctx, TraceCorrelation{})
+}
+
+// NewContext returns a copy of ctx carrying fields in addition to the ones
+// already in ctx. Loggers returned by Ctx include them.
+// Usage: ctx = logger.NewContext(ctx, zap.String("request_id", id))
+func NewContext(ctx context.Context, fields ...zap.Field) context.Context {
+	existing := ContextFields(ctx)
+	// Copy so contexts derived from the same parent don't share the array.
+	merged := make([]zap.Field, 0, len(existing)+len(fields))
+	merged = append(merged, existing...)
+	merged = append(merged, fields...)
+	return context.WithValue(ctx, contextFieldsKey{}, merged)
+}
+
+// ContextFields returns the fields added to ctx with NewContext.
+func ContextFields(ctx context.Context) []zap.Field {
 	if ctx == nil {
 		return nil
 	}
+	fields, _ := ctx.Value(contextFieldsKey{}).([]zap.Field)
+	return fields
+}
+
+// ContextWithLogger returns a copy of ctx carrying l, e.g. a request-scoped logger.
+func ContextWithLogger(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, contextLoggerKey{}, l)
+}
+
+// FromContext returns the logger stored with ContextWithLogger, or the global logger.
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(contextLoggerKey{}).(*Logger); ok && l != nil {
+			return l
+		}
+	}
+	return GetLogger()
+}
 
-	var fields []zap.Field
+// RegisterContextExtractor adds an extractor whose fields are included by Ctx.
+// Registering an existing name replaces that extractor.
+func RegisterContextExtractor(name string, extractor ContextExtractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
 
-	// OpenTelemetry tracing
-	span := trace.SpanFromContext(ctx)
-	if span.SpanContext().IsValid() {
-		spanCtx := span.SpanContext()
-		fields = append(fields,
-			zap.String("trace_id", spanCtx.TraceID().String()),
-			zap.String("span_id", spanCtx.SpanID().String()),
-		)
+	var next []namedExtractor
+	replaced := false
+	for _, e := range loadExtractors() {
+		if e.name == name {
+			e.extractor = extractor
+			replaced = true
+		}
+		next = append(next, e)
 	}
+	if !replaced {
+		next = append(next, namedExtractor{name: name, extractor: extractor})
+	}
+	extractors.Store(&next)
+}
 
+// UnregisterContextExtractor removes the extractor registered under name.
+func UnregisterContextExtractor(name string) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+
+	var next []namedExtractor
+	for _, e := range loadExtractors() {
+		if e.name != name {
+			next = append(next, e)
+		}
+	}
+	extractors.Store(&next)
+}
+
+// ContextValueExtractor returns an extractor logging ctx.Value(key) under field,
+// when the value is present.
+func ContextValueExtractor(key interface{}, field string) ContextExtractor {
+	return func(ctx context.Context) []zap.Field {
+		if v := ctx.Value(key); v != nil {
+			return []zap.Field{zap.Any(field, v)}
+		}
+		return nil
+	}
+}
+
+func loadExtractors() []namedExtractor {
+	if p := extractors.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// ExtractContextFields returns the trace fields, the fields added with
+// NewContext and the fields of the registered extractors, in that order.
+func ExtractContextFields(ctx context.Context) []zap.Field {
+	return extractContextFields(ctx, TraceCorrelation{})
+}
+
+func extractContextFields(ctx context.Context, tc TraceCorrelation) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+	fields := ExtractTraceFieldsWith(ctx, tc)
+	fields = append(fields, ContextFields(ctx)...)
+	for _, e := range loadExtractors() {
+		fields = append(fields, e.extractor(ctx)...)
+	}
 	return fields
 }
+
+// Ctx returns the logger of ctx (see FromContext) with all context fields
+// (see ExtractContextFields).
+// Usage: logger.Ctx(ctx).Info("order created")
+func Ctx(ctx context.Context) *Logger {
+	return FromContext(ctx).Ctx(ctx)
+}
@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+type tenantKey struct{}
+
+func TestNewContext(t *testing.T) {
+	ctx := NewContext(context.Background(), zap.String("request_id", "req-1"))
+	child1 := NewContext(ctx, zap.String("user_id", "u-1"))
+	child2 := NewContext(ctx, zap.String("user_id", "u-2"))
+
+	if got := len(ContextFields(ctx)); got != 1 {
+		t.Errorf("parent has %d fields, want 1", got)
+	}
+	// Siblings must not overwrite each other's fields.
+	if got := ContextFields(child1)[1].String; got != "u-1" {
+		t.Errorf("child1 user_id = %s, want u-1", got)
+	}
+	if got := ContextFields(child2)[1].String; got != "u-2" {
+		t.Errorf("child2 user_id = %s, want u-2", got)
+	}
+	if ContextFields(context.Background()) != nil {
+		t.Error("empty context should have no fields")
+	}
+}
+
+func TestCtx(t *testing.T) {
+	logger, out := newJSONTestLogger(t)
+
+	RegisterContextExtractor("tenant", ContextValueExtractor(tenantKey{}, "tenant"))
+	defer UnregisterContextExtractor("tenant")
+
+	tracer := trace.NewTracerProvider().Tracer("test")
+	ctx, span := tracer.Start(context.Background(), "test-span")
+	defer span.End()
+
+	ctx = context.WithValue(ctx, tenantKey{}, "acme")
+	ctx = NewContext(ctx, zap.String("request_id", "req-1"))
+	ctx = ContextWithLogger(ctx, logger)
+
+	Ctx(ctx).Info("with context")
+
+	entry := decodeLastLine(t, out)
+	want := map[string]interface{}{
+		"msg":        "with context",
+		"trace_id":   span.SpanContext().TraceID().String(),
+		"span_id":    span.SpanContext().SpanID().String(),
+		"request_id": "req-1",
+		"tenant":     "acme",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("%s = %v, want %v", key, entry[key], value)
+		}
+	}
+
+	logger.Sugar().Ctx(ctx).Infow("sugared")
+	if entry := decodeLastLine(t, out); entry["request_id"] != "req-1" || entry["tenant"] != "acme" {
+		t.Errorf("sugared logger misses context fields: %v", entry)
+	}
+}
+
+func TestContextExtractorRegistry(t *testing.T) {
+	extractor := func(value string) ContextExtractor {
+		return func(ctx context.Context) []zap.Field {
+			return []zap.Field{zap.String("source", value)}
+		}
+	}
+
+	RegisterContextExtractor("a", extractor("first"))
+	RegisterContextExtractor("b", extractor("second"))
+	RegisterContextExtractor("a", extractor("replaced"))
+
+	fields := ExtractContextFields(context.Background())
+	if len(fields) != 2 || fields[0].String != "replaced" || fields[1].String != "second" {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+
+	UnregisterContextExtractor("a")
+	UnregisterContextExtractor("b")
+	if fields := ExtractContextFields(context.Background()); len(fields) != 0 {
+		t.Errorf("unexpected fields after unregister: %v", fields)
+	}
+	if ExtractContextFields(nil) != nil { //nolint:staticcheck // nil context is handled
+		t.Error("nil context should have no fields")
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	Init(WithLevel(LevelInfo), WithFormat(FormatJSON))
+
+	if FromContext(context.Background()) != GetLogger() {
+		t.Error("FromContext() without logger should return the global logger")
+	}
+
+	logger, _ := newJSONTestLogger(t)
+	ctx := ContextWithLogger(context.Background(), logger)
+	if FromContext(ctx) != logger {
+		t.Error("FromContext() should return the logger stored in the context")
+	}
+}
@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Trace correlation format constants
+const (
+	TraceFormatOTel    = "otel"    // trace_id, span_id (hex)
+	TraceFormatDatadog = "datadog" // dd.trace_id, dd.span_id (decimal), dd.service, dd.env, dd.version
+	TraceFormatGCP     = "gcp"     // logging.googleapis.com/trace, spanId, trace_sampled
+	TraceFormatW3C     = "w3c"     // traceparent, trace_flags, sampled
+)
+
+// TraceCorrelation selects the fields logged for the span in the context,
+// so that log backends can link entries to their traces.
+type TraceCorrelation struct {
+	Formats []string `mapstructure:"FORMATS"` // any of the TraceFormat* constants (default: otel)
+
+	// Datadog unified service tags, logged with the datadog format when set.
+	Service string `mapstructure:"SERVICE"`
+	Env     string `mapstructure:"ENV"`
+	Version string `mapstructure:"VERSION"`
+
+	// GCPProjectID is required by the gcp format.
+	GCPProjectID string `mapstructure:"GCP_PROJECT_ID"`
+}
+
+// validate checks if the trace correlation is valid.
+func (tc TraceCorrelation) validate() error {
+	for _, format := range tc.Formats {
+		switch format {
+		case TraceFormatOTel, TraceFormatDatadog, TraceFormatW3C:
+			// valid
+		case TraceFormatGCP:
+			if tc.GCPProjectID == "" {
+				return fmt.Errorf("gcp project id is required for gcp trace format")
+			}
+		default:
+			return fmt.Errorf("invalid trace format: %s", format)
+		}
+	}
+	return nil
+}
+
+// ExtractTraceFieldsWith extracts the fields of the span in ctx in the formats of tc.
+func ExtractTraceFieldsWith(ctx context.Context, tc TraceCorrelation) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsValid() {
+		return nil
+	}
+
+	formats := tc.Formats
+	if len(formats) == 0 {
+		formats = []string{TraceFormatOTel}
+	}
+
+	var fields []zap.Field
+	for _, format := range formats {
+		switch format {
+		case TraceFormatOTel:
+			fields = append(fields,
+				zap.String("trace_id", spanCtx.TraceID().String()),
+				zap.String("span_id", spanCtx.SpanID().String()),
+			)
+		case TraceFormatDatadog:
+			fields = append(fields, tc.datadogFields(spanCtx)...)
+		case TraceFormatGCP:
+			fields = append(fields,
+				zap.String("logging.googleapis.com/trace",
+					"projects/"+tc.GCPProjectID+"/traces/"+spanCtx.TraceID().String()),
+				zap.String("logging.googleapis.com/spanId", spanCtx.SpanID().String()),
+				zap.Bool("logging.googleapis.com/trace_sampled", spanCtx.IsSampled()),
+			)
+		case TraceFormatW3C:
+			fields = append(fields,
+				zap.String("traceparent", Traceparent(spanCtx)),
+				zap.String("trace_flags", spanCtx.TraceFlags().String()),
+				zap.Bool("sampled", spanCtx.IsSampled()),
+			)
+		}
+	}
+	return fields
+}
+
+func (tc TraceCorrelation) datadogFields(spanCtx trace.SpanContext) []zap.Field {
+	fields := []zap.Field{
+		zap.String("dd.trace_id", DatadogTraceID(spanCtx.TraceID())),
+		zap.String("dd.span_id", DatadogSpanID(spanCtx.SpanID())),
+	}
+	if tc.Service != "" {
+		fields = append(fields, zap.String("dd.service", tc.Service))
+	}
+	if tc.Env != "" {
+		fields = append(fields, zap.String("dd.env", tc.Env))
+	}
+	if tc.Version != "" {
+		fields = append(fields, zap.String("dd.version", tc.Version))
+	}
+	return fields
+}
+
+// DatadogTraceID converts an OpenTelemetry trace ID to Datadog's format:
+// the lower 64 bits as a decimal string.
+func DatadogTraceID(id trace.TraceID) string {
+	return strconv.FormatUint(binary.BigEndian.Uint64(id[8:]), 10)
+}
+
+// DatadogSpanID converts an OpenTelemetry span ID to Datadog's decimal format.
+func DatadogSpanID(id trace.SpanID) string {
+	return strconv.FormatUint(binary.BigEndian.Uint64(id[:]), 10)
+}
+
+// Traceparent formats the span context as a W3C traceparent header value,
+// e.g. "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+func Traceparent(spanCtx trace.SpanContext) string {
+	return "00-" + spanCtx.TraceID().String() + "-" + spanCtx.SpanID().String() + "-" + spanCtx.TraceFlags().String()
+}
@@ -0,0 +1,154 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func testSpanContext(t *testing.T) context.Context {
+	t.Helper()
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	return trace.ContextWithSpanContext(context.Background(), spanCtx)
+}
+
+func TestExtractTraceFieldsWith(t *testing.T) {
+	ctx := testSpanContext(t)
+
+	tests := []struct {
+		name string
+		tc   TraceCorrelation
+		want map[string]interface{}
+	}{
+		{
+			name: "default otel",
+			tc:   TraceCorrelation{},
+			want: map[string]interface{}{
+				"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+				"span_id":  "00f067aa0ba902b7",
+			},
+		},
+		{
+			name: "datadog",
+			tc:   TraceCorrelation{Formats: []string{TraceFormatDatadog}, Service: "orders", Env: "prod", Version: "1.2.3"},
+			want: map[string]interface{}{
+				"dd.trace_id": "11803532876627986230",
+				"dd.span_id":  "67667974448284343",
+				"dd.service":  "orders",
+				"dd.env":      "prod",
+				"dd.version":  "1.2.3",
+			},
+		},
+		{
+			name: "gcp",
+			tc:   TraceCorrelation{Formats: []string{TraceFormatGCP}, GCPProjectID: "my-project"},
+			want: map[string]interface{}{
+				"logging.googleapis.com/trace":         "projects/my-project/traces/4bf92f3577b34da6a3ce929d0e0e4736",
+				"logging.googleapis.com/spanId":        "00f067aa0ba902b7",
+				"logging.googleapis.com/trace_sampled": true,
+			},
+		},
+		{
+			name: "w3c and otel",
+			tc:   TraceCorrelation{Formats: []string{TraceFormatOTel, TraceFormatW3C}},
+			want: map[string]interface{}{
+				"trace_id":    "4bf92f3577b34da6a3ce929d0e0e4736",
+				"span_id":     "00f067aa0ba902b7",
+				"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				"trace_flags": "01",
+				"sampled":     true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := ExtractTraceFieldsWith(ctx, tt.tc)
+			if len(fields) != len(tt.want) {
+				t.Fatalf("got %d fields, want %d: %v", len(fields), len(tt.want), fields)
+			}
+			for _, f := range fields {
+				var got interface{} = f.String
+				if f.Key == "sampled" || f.Key == "logging.googleapis.com/trace_sampled" {
+					got = f.Integer == 1
+				}
+				if got != tt.want[f.Key] {
+					t.Errorf("%s = %v, want %v", f.Key, got, tt.want[f.Key])
+				}
+			}
+		})
+	}
+
+	if fields := ExtractTraceFieldsWith(context.Background(), TraceCorrelation{Formats: []string{TraceFormatDatadog}}); fields != nil {
+		t.Errorf("context without span should have no fields, got %v", fields)
+	}
+}
+
+func TestLogger_TraceCorrelation(t *testing.T) {
+	logger, out := newJSONTestLogger(t,
+		WithDatadogCorrelation("orders", "prod", "1.2.3"),
+		WithTraceFormats(TraceFormatDatadog, TraceFormatW3C),
+	)
+	ctx := testSpanContext(t)
+
+	logger.Named("api").Trace(ctx).Info("traced")
+	entry := decodeLastLine(t, out)
+	if entry["dd.trace_id"] != "11803532876627986230" || entry["traceparent"] == nil || entry["trace_id"] != nil {
+		t.Errorf("unexpected trace fields: %v", entry)
+	}
+
+	logger.Ctx(ctx).Info("ctx")
+	entry = decodeLastLine(t, out)
+	if entry["dd.span_id"] != "67667974448284343" || entry["dd.service"] != "orders" {
+		t.Errorf("unexpected ctx fields: %v", entry)
+	}
+}
+
+func TestTraceCorrelation_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr bool
+	}{
+		{name: "default", opts: nil, wantErr: false},
+		{name: "datadog", opts: []Option{WithDatadogCorrelation("svc", "", "")}, wantErr: false},
+		{name: "gcp", opts: []Option{WithGCPCorrelation("project")}, wantErr: false},
+		{name: "gcp without project", opts: []Option{WithTraceFormats(TraceFormatGCP)}, wantErr: true},
+		{name: "unknown format", opts: []Option{WithTraceFormats("zipkin")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newConfig(tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAppendTraceFormat(t *testing.T) {
+	cfg, _ := newConfig(WithDatadogCorrelation("svc", "", ""), WithGCPCorrelation("p"), WithDatadogCorrelation("svc", "", ""))
+	want := []string{TraceFormatOTel, TraceFormatDatadog, TraceFormatGCP}
+	if len(cfg.TraceCorrelation.Formats) != len(want) {
+		t.Fatalf("Formats = %v, want %v", cfg.TraceCorrelation.Formats, want)
+	}
+	for i := range want {
+		if cfg.TraceCorrelation.Formats[i] != want[i] {
+			t.Errorf("Formats = %v, want %v", cfg.TraceCorrelation.Formats, want)
+		}
+	}
+}
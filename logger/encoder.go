@@ -0,0 +1,217 @@
+package logger
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// encodedField is a field rendered by a fieldCollector.
+type encodedField struct {
+	key   string
+	value string
+}
+
+// fieldCollector is a zapcore.ObjectEncoder rendering fields as ordered
+// key/value strings for the text-based encoders. Nested objects and
+// namespaces are flattened into dotted keys; arrays and reflected values are
+// rendered as JSON.
+type fieldCollector struct {
+	cfg    *zapcore.EncoderConfig
+	prefix string // namespaces and enclosing objects, e.g. "http.request."
+	fields []encodedField
+}
+
+func (c *fieldCollector) clone() *fieldCollector {
+	return &fieldCollector{
+		cfg:    c.cfg,
+		prefix: c.prefix,
+		fields: append([]encodedField(nil), c.fields...),
+	}
+}
+
+func (c *fieldCollector) add(key, value string) {
+	c.fields = append(c.fields, encodedField{key: c.prefix + key, value: value})
+}
+
+func (c *fieldCollector) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	enc := zapcore.NewMapObjectEncoder()
+	err := enc.AddArray(key, arr)
+	c.addJSON(key, enc.Fields[key])
+	return err
+}
+
+func (c *fieldCollector) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	prefix, n := c.prefix, len(c.fields)
+	c.prefix = prefix + key + "."
+	err := obj.MarshalLogObject(c)
+	c.prefix = prefix
+	if len(c.fields) == n {
+		c.add(key, "{}")
+	}
+	return err
+}
+
+func (c *fieldCollector) AddBinary(key string, value []byte) {
+	c.add(key, base64.StdEncoding.EncodeToString(value))
+}
+
+func (c *fieldCollector) AddByteString(key string, value []byte) {
+	c.add(key, string(value))
+}
+
+func (c *fieldCollector) AddBool(key string, value bool) {
+	c.add(key, strconv.FormatBool(value))
+}
+
+func (c *fieldCollector) AddComplex128(key string, value complex128) {
+	c.add(key, strconv.FormatComplex(value, 'g', -1, 128))
+}
+
+func (c *fieldCollector) AddComplex64(key string, value complex64) {
+	c.add(key, strconv.FormatComplex(complex128(value), 'g', -1, 64))
+}
+
+func (c *fieldCollector) AddDuration(key string, value time.Duration) {
+	if c.cfg.EncodeDuration == nil {
+		c.add(key, value.String())
+		return
+	}
+	c.add(key, encodePrimitive(func(enc zapcore.PrimitiveArrayEncoder) { c.cfg.EncodeDuration(value, enc) }))
+}
+
+func (c *fieldCollector) AddFloat64(key string, value float64) {
+	c.add(key, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+func (c *fieldCollector) AddFloat32(key string, value float32) {
+	c.add(key, strconv.FormatFloat(float64(value), 'g', -1, 32))
+}
+
+func (c *fieldCollector) AddInt(key string, value int)     { c.AddInt64(key, int64(value)) }
+func (c *fieldCollector) AddInt32(key string, value int32) { c.AddInt64(key, int64(value)) }
+func (c *fieldCollector) AddInt16(key string, value int16) { c.AddInt64(key, int64(value)) }
+func (c *fieldCollector) AddInt8(key string, value int8)   { c.AddInt64(key, int64(value)) }
+
+func (c *fieldCollector) AddInt64(key string, value int64) {
+	c.add(key, strconv.FormatInt(value, 10))
+}
+
+func (c *fieldCollector) AddString(key, value string) {
+	c.add(key, value)
+}
+
+func (c *fieldCollector) AddTime(key string, value time.Time) {
+	if c.cfg.EncodeTime == nil {
+		c.add(key, value.Format(time.RFC3339Nano))
+		return
+	}
+	c.add(key, encodePrimitive(func(enc zapcore.PrimitiveArrayEncoder) { c.cfg.EncodeTime(value, enc) }))
+}
+
+func (c *fieldCollector) AddUint(key string, value uint)       { c.AddUint64(key, uint64(value)) }
+func (c *fieldCollector) AddUint32(key string, value uint32)   { c.AddUint64(key, uint64(value)) }
+func (c *fieldCollector) AddUint16(key string, value uint16)   { c.AddUint64(key, uint64(value)) }
+func (c *fieldCollector) AddUint8(key string, value uint8)     { c.AddUint64(key, uint64(value)) }
+func (c *fieldCollector) AddUintptr(key string, value uintptr) { c.AddUint64(key, uint64(value)) }
+
+func (c *fieldCollector) AddUint64(key string, value uint64) {
+	c.add(key, strconv.FormatUint(value, 10))
+}
+
+func (c *fieldCollector) AddReflected(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	c.add(key, string(data))
+	return nil
+}
+
+func (c *fieldCollector) OpenNamespace(key string) {
+	c.prefix += key + "."
+}
+
+func (c *fieldCollector) addJSON(key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		c.add(key+"Error", err.Error())
+		return
+	}
+	c.add(key, string(data))
+}
+
+// primitiveCapture records the values appended by zap's time, level, caller,
+// name and duration encoders.
+type primitiveCapture struct {
+	values []string
+}
+
+// encodePrimitive runs an encoder callback and returns what it appended.
+func encodePrimitive(encode func(zapcore.PrimitiveArrayEncoder)) string {
+	capture := &primitiveCapture{}
+	encode(capture)
+	return strings.Join(capture.values, " ")
+}
+
+func (p *primitiveCapture) append(value string) { p.values = append(p.values, value) }
+
+func (p *primitiveCapture) AppendBool(v bool)         { p.append(strconv.FormatBool(v)) }
+func (p *primitiveCapture) AppendByteString(v []byte) { p.append(string(v)) }
+func (p *primitiveCapture) AppendComplex128(v complex128) {
+	p.append(strconv.FormatComplex(v, 'g', -1, 128))
+}
+func (p *primitiveCapture) AppendComplex64(v complex64) {
+	p.append(strconv.FormatComplex(complex128(v), 'g', -1, 64))
+}
+func (p *primitiveCapture) AppendFloat64(v float64) { p.append(strconv.FormatFloat(v, 'g', -1, 64)) }
+func (p *primitiveCapture) AppendFloat32(v float32) {
+	p.append(strconv.FormatFloat(float64(v), 'g', -1, 32))
+}
+func (p *primitiveCapture) AppendInt(v int)                { p.append(strconv.Itoa(v)) }
+func (p *primitiveCapture) AppendInt64(v int64)            { p.append(strconv.FormatInt(v, 10)) }
+func (p *primitiveCapture) AppendInt32(v int32)            { p.append(strconv.FormatInt(int64(v), 10)) }
+func (p *primitiveCapture) AppendInt16(v int16)            { p.append(strconv.FormatInt(int64(v), 10)) }
+func (p *primitiveCapture) AppendInt8(v int8)              { p.append(strconv.FormatInt(int64(v), 10)) }
+func (p *primitiveCapture) AppendString(v string)          { p.append(v) }
+func (p *primitiveCapture) AppendUint(v uint)              { p.append(strconv.FormatUint(uint64(v), 10)) }
+func (p *primitiveCapture) AppendUint64(v uint64)          { p.append(strconv.FormatUint(v, 10)) }
+func (p *primitiveCapture) AppendUint32(v uint32)          { p.append(strconv.FormatUint(uint64(v), 10)) }
+func (p *primitiveCapture) AppendUint16(v uint16)          { p.append(strconv.FormatUint(uint64(v), 10)) }
+func (p *primitiveCapture) AppendUint8(v uint8)            { p.append(strconv.FormatUint(uint64(v), 10)) }
+func (p *primitiveCapture) AppendUintptr(v uintptr)        { p.append(strconv.FormatUint(uint64(v), 10)) }
+func (p *primitiveCapture) AppendDuration(v time.Duration) { p.append(v.String()) }
+func (p *primitiveCapture) AppendTime(v time.Time)         { p.append(v.Format(time.RFC3339Nano)) }
+
+// logfmtKey replaces the characters not allowed in logfmt keys.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// logfmtValue quotes value if it is empty or contains spaces, quotes, equal
+// signs or non-printable characters.
+func logfmtValue(value string) string {
+	if value == "" {
+		return `""`
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return strconv.Quote(value)
+		}
+	}
+	return value
+}
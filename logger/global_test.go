@@ -0,0 +1,177 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/laziness-coders/go-utils/generic"
+	"go.uber.org/zap"
+)
+
+// resetGlobal restores the uninitialized global state for the test.
+func resetGlobal(t *testing.T) {
+	t.Helper()
+	t.Cleanup(swapGlobal(newNopLogger(), false))
+}
+
+func TestGlobal_NoopBeforeInit(t *testing.T) {
+	resetGlobal(t)
+
+	// None of these may panic before Init.
+	Info("info")
+	Debugf("debug %d", 1)
+	Trace(context.Background()).Warn("trace")
+	Ctx(context.Background()).Error("ctx")
+	Sugar().Infow("sugar", "k", "v")
+	With(zap.String("k", "v")).Info("with")
+	Named("payments").Info("named")
+	FromContext(context.Background()).Info("from context")
+
+	if err := SetLevel(LevelWarn); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	if Level() != LevelWarn {
+		t.Errorf("Level() = %s, want %s", Level(), LevelWarn)
+	}
+	if err := Sync(); err != nil {
+		t.Errorf("Sync() error = %v", err)
+	}
+}
+
+func TestGlobal_StderrFallback(t *testing.T) {
+	resetGlobal(t)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	err = UseStderrFallback(LevelWarn)
+	os.Stderr = stderr
+	if err != nil {
+		t.Fatalf("UseStderrFallback() error = %v", err)
+	}
+
+	Info("hidden")
+	Warn("shown")
+	_ = w.Close()
+	out, _ := io.ReadAll(r)
+
+	if !strings.Contains(string(out), "shown") || strings.Contains(string(out), "hidden") {
+		t.Errorf("unexpected fallback output: %q", out)
+	}
+
+	if err := UseStderrFallback("verbose"); err == nil {
+		t.Error("UseStderrFallback() should reject invalid levels")
+	}
+}
+
+func TestGlobal_FallbackDoesNotReplaceInit(t *testing.T) {
+	resetGlobal(t)
+
+	out := &TestOutput{}
+	Init(WithDev(false), WithSink(Sink{Format: FormatJSON, Output: OutputWriter, Writer: out}))
+	initialized := GetLogger()
+
+	if err := UseStderrFallback(LevelInfo); err != nil {
+		t.Fatalf("UseStderrFallback() error = %v", err)
+	}
+	if GetLogger() != initialized {
+		t.Error("the fallback should not replace an initialized logger")
+	}
+}
+
+func TestGlobal_ConcurrentInit(t *testing.T) {
+	resetGlobal(t)
+	out := &TestOutput{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				Info("message", zap.Int("j", j))
+				Trace(context.Background()).Debug("trace")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				Init(WithDev(false), WithSink(Sink{Format: FormatJSON, Output: OutputWriter, Writer: out}))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if GetLogger() == nil {
+		t.Fatal("GetLogger() should never be nil")
+	}
+}
+
+func TestReplaceForTest(t *testing.T) {
+	outer := ReplaceForTest(t)
+
+	t.Run("inner", func(t *testing.T) {
+		inner := ReplaceForTest(t, WithLevel(LevelWarn))
+		Info("dropped")
+		Warn("inner message", zap.String("k", "v"))
+
+		lines := inner.Lines()
+		if len(lines) != 1 || !strings.Contains(lines[0], `"msg":"inner message"`) || !strings.Contains(lines[0], `"k":"v"`) {
+			t.Errorf("inner output = %q", lines)
+		}
+	})
+
+	Info("outer message")
+	if lines := outer.Lines(); len(lines) != 1 || !strings.Contains(lines[0], "outer message") {
+		t.Errorf("outer output = %q, the outer logger should be restored", lines)
+	}
+
+	outer.Reset()
+	if outer.Lines() != nil {
+		t.Error("Reset() should discard the output")
+	}
+}
+
+func TestReplaceForTest_RestoresState(t *testing.T) {
+	resetGlobal(t)
+	cfg := Config{
+		Level: LevelInfo,
+		IsDev: generic.ToPointer(false),
+		Sinks: []Sink{{Format: FormatJSON, Output: OutputWriter, Writer: &TestOutput{}}},
+	}
+	if err := InitFromConfig(cfg); err != nil {
+		t.Fatalf("InitFromConfig() error = %v", err)
+	}
+	configuredLogger := GetLogger()
+
+	t.Run("replaced", func(t *testing.T) {
+		out := ReplaceForTest(t)
+		// The test logger counts as initialized: the fallback keeps off it.
+		if err := UseStderrFallback(LevelInfo); err != nil {
+			t.Fatalf("UseStderrFallback() error = %v", err)
+		}
+		Info("captured")
+		if len(out.Lines()) != 1 {
+			t.Errorf("output = %q, want the entry", out.Lines())
+		}
+	})
+
+	if GetLogger() != configuredLogger {
+		t.Fatal("the configured logger should be restored")
+	}
+	// InitFromConfig still updates the restored logger in place.
+	cfg.Level = LevelWarn
+	if err := InitFromConfig(cfg); err != nil {
+		t.Fatalf("InitFromConfig() error = %v", err)
+	}
+	if GetLogger() != configuredLogger || Level() != LevelWarn {
+		t.Errorf("level change after ReplaceForTest: replaced = %v, level = %s", GetLogger() != configuredLogger, Level())
+	}
+}
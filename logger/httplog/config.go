@@ -0,0 +1,220 @@
+package httplog
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/laziness-coders/go-utils/logger"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultRequestIDHeader is the header read and written with the request ID.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength caps accepted request IDs to keep log lines bounded.
+const maxRequestIDLength = 128
+
+// Config defines the request logging configuration.
+type Config struct {
+	Logger          *logger.Logger // base logger (default: logger of the request context, i.e. the global logger)
+	SkipPaths       []string       // paths not logged, e.g. health checks (request IDs still apply)
+	RequestHeaders  []string       // request headers to log
+	ResponseHeaders []string       // response headers to log
+
+	// Body logging, disabled when the limit is 0. Bodies are logged up to
+	// the limit, in bytes, and redacted with Redaction.
+	RequestBodyLimit  int
+	ResponseBodyLimit int
+	// Redaction masks sensitive headers and body values
+	// (default: logger.DefaultRedaction()).
+	Redaction *logger.Redaction
+
+	// StatusLevels sets the log level by status class, e.g. 5 for 5xx
+	// (default: info, warn for 4xx, error for 5xx).
+	StatusLevels map[int]string
+
+	RequestIDHeader    string        // default: X-Request-ID
+	RequestIDGenerator func() string // default: 16 random bytes, hex encoded
+	// TrustForwardedFor takes the remote IP from X-Forwarded-For or
+	// X-Real-IP. Only enable it behind a proxy that sets these headers.
+	TrustForwardedFor bool
+
+	skipPaths    map[string]struct{}
+	statusLevels map[int]zapcore.Level
+}
+
+// Option is a functional option for configuring the middleware.
+type Option func(*Config)
+
+// WithLogger sets the base logger of the request loggers.
+func WithLogger(l *logger.Logger) Option {
+	return func(cfg *Config) {
+		cfg.Logger = l
+	}
+}
+
+// WithSkipPaths disables logging for the given request paths.
+func WithSkipPaths(paths ...string) Option {
+	return func(cfg *Config) {
+		cfg.SkipPaths = append(cfg.SkipPaths, paths...)
+	}
+}
+
+// WithRequestHeaders logs the given request headers.
+func WithRequestHeaders(names ...string) Option {
+	return func(cfg *Config) {
+		cfg.RequestHeaders = append(cfg.RequestHeaders, names...)
+	}
+}
+
+// WithResponseHeaders logs the given response headers.
+func WithResponseHeaders(names ...string) Option {
+	return func(cfg *Config) {
+		cfg.ResponseHeaders = append(cfg.ResponseHeaders, names...)
+	}
+}
+
+// WithRequestBody logs up to limit bytes of request bodies.
+func WithRequestBody(limit int) Option {
+	return func(cfg *Config) {
+		cfg.RequestBodyLimit = limit
+	}
+}
+
+// WithResponseBody logs up to limit bytes of response bodies.
+func WithResponseBody(limit int) Option {
+	return func(cfg *Config) {
+		cfg.ResponseBodyLimit = limit
+	}
+}
+
+// WithRedaction sets the rules masking logged headers and bodies.
+func WithRedaction(rules logger.Redaction) Option {
+	return func(cfg *Config) {
+		cfg.Redaction = &rules
+	}
+}
+
+// WithStatusLevel sets the log level of a status class, e.g. 4 for 4xx.
+func WithStatusLevel(class int, level string) Option {
+	return func(cfg *Config) {
+		if cfg.StatusLevels == nil {
+			cfg.StatusLevels = make(map[int]string)
+		}
+		cfg.StatusLevels[class] = level
+	}
+}
+
+// WithRequestIDHeader sets the header read and written with the request ID.
+func WithRequestIDHeader(name string) Option {
+	return func(cfg *Config) {
+		cfg.RequestIDHeader = name
+	}
+}
+
+// WithRequestIDGenerator sets the function generating missing request IDs.
+func WithRequestIDGenerator(generate func() string) Option {
+	return func(cfg *Config) {
+		cfg.RequestIDGenerator = generate
+	}
+}
+
+// WithTrustForwardedFor takes the remote IP from X-Forwarded-For or X-Real-IP.
+func WithTrustForwardedFor() Option {
+	return func(cfg *Config) {
+		cfg.TrustForwardedFor = true
+	}
+}
+
+// newConfig creates a new Config with the given options applied.
+func newConfig(opts ...Option) (*Config, error) {
+	cfg := &Config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	cfg.setDefaults()
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return cfg, nil
+}
+
+// setDefaults applies default values to the configuration.
+func (c *Config) setDefaults() {
+	if c.Redaction == nil {
+		rules := logger.DefaultRedaction()
+		c.Redaction = &rules
+	}
+	levels := map[int]string{
+		1: logger.LevelInfo,
+		2: logger.LevelInfo,
+		3: logger.LevelInfo,
+		4: logger.LevelWarn,
+		5: logger.LevelError,
+	}
+	for class, level := range c.StatusLevels {
+		levels[class] = level
+	}
+	c.StatusLevels = levels
+	if c.RequestIDHeader == "" {
+		c.RequestIDHeader = DefaultRequestIDHeader
+	}
+	if c.RequestIDGenerator == nil {
+		c.RequestIDGenerator = generateRequestID
+	}
+
+	c.skipPaths = make(map[string]struct{}, len(c.SkipPaths))
+	for _, path := range c.SkipPaths {
+		c.skipPaths[path] = struct{}{}
+	}
+	for i, name := range c.RequestHeaders {
+		c.RequestHeaders[i] = http.CanonicalHeaderKey(name)
+	}
+	for i, name := range c.ResponseHeaders {
+		c.ResponseHeaders[i] = http.CanonicalHeaderKey(name)
+	}
+}
+
+// statusLevelNames are the levels accepted for status classes. Levels above
+// error would panic or exit the process while serving a request.
+var statusLevelNames = map[string]zapcore.Level{
+	logger.LevelDebug: zapcore.DebugLevel,
+	logger.LevelInfo:  zapcore.InfoLevel,
+	logger.LevelWarn:  zapcore.WarnLevel,
+	logger.LevelError: zapcore.ErrorLevel,
+}
+
+// validate checks if the configuration is valid and parses the status levels.
+func (c *Config) validate() error {
+	if c.RequestBodyLimit < 0 || c.ResponseBodyLimit < 0 {
+		return fmt.Errorf("body limits must not be negative")
+	}
+	c.statusLevels = make(map[int]zapcore.Level, len(c.StatusLevels))
+	for class, name := range c.StatusLevels {
+		if class < 1 || class > 5 {
+			return fmt.Errorf("invalid status class: %d", class)
+		}
+		level, ok := statusLevelNames[name]
+		if !ok {
+			return fmt.Errorf("invalid log level for %dxx: %s", class, name)
+		}
+		c.statusLevels[class] = level
+	}
+	return nil
+}
+
+// levelFor returns the log level of a response status.
+func (c *Config) levelFor(status int) zapcore.Level {
+	if level, ok := c.statusLevels[status/100]; ok {
+		return level
+	}
+	return zapcore.InfoLevel
+}
+
+func generateRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
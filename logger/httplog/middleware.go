@@ -0,0 +1,302 @@
+// Package httplog provides a net/http middleware logging requests with the logger package.
+package httplog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/laziness-coders/go-utils/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const accessLogMessage = "http request"
+
+type requestIDKey struct{}
+
+// RequestID returns the request ID set by the middleware, or "".
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Middleware returns a middleware logging every request once it completes.
+// It reads the request ID from the request, or generates one, echoes it in
+// the response, and puts a logger with the request_id field into the request
+// context: handlers log with logger.Ctx(r.Context()) to also get trace fields.
+// Usage:
+//
+//	mw, err := httplog.Middleware(httplog.WithSkipPaths("/healthz"))
+//	http.ListenAndServe(":8080", mw(mux))
+func Middleware(opts ...Option) (func(http.Handler) http.Handler, error) {
+	cfg, err := newConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cfg.serve(next, w, r)
+		})
+	}, nil
+}
+
+func (c *Config) serve(next http.Handler, w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	id := c.requestID(r)
+	w.Header().Set(c.RequestIDHeader, id)
+
+	base := c.Logger
+	if base == nil {
+		base = logger.FromContext(r.Context())
+	}
+	reqLogger := base.With(zap.String("request_id", id))
+
+	ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+	r = r.WithContext(logger.ContextWithLogger(ctx, reqLogger))
+
+	if _, skip := c.skipPaths[r.URL.Path]; skip {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	var reqBody *bodyCapture
+	if c.RequestBodyLimit > 0 && r.Body != nil && r.Body != http.NoBody {
+		reqBody = captureRequestBody(r, c.RequestBodyLimit)
+	}
+	rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+	if c.ResponseBodyLimit > 0 {
+		rw.body = &bodyCapture{limit: c.ResponseBodyLimit}
+	}
+
+	defer func() {
+		// Log panics as 500 before passing them on.
+		if p := recover(); p != nil {
+			if !rw.wroteHeader {
+				rw.status = http.StatusInternalServerError
+			}
+			c.log(reqLogger, r, rw, reqBody, start, zap.Any("panic", p))
+			panic(p)
+		}
+		c.log(reqLogger, r, rw, reqBody, start)
+	}()
+	next.ServeHTTP(rw, r)
+}
+
+// requestID returns the valid ID of the request header, or a new one.
+func (c *Config) requestID(r *http.Request) string {
+	if id := r.Header.Get(c.RequestIDHeader); validRequestID(id) {
+		return id
+	}
+	return c.RequestIDGenerator()
+}
+
+// validRequestID accepts short IDs of safe characters, so that clients
+// cannot inject arbitrary content into logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Config) log(l *logger.Logger, r *http.Request, rw *responseWriter, reqBody *bodyCapture, start time.Time, extra ...zap.Field) {
+	level := c.levelFor(rw.status)
+	l = l.Trace(r.Context())
+	ce := l.Check(level, accessLogMessage)
+	if ce == nil {
+		return
+	}
+
+	// The pattern is set by http.ServeMux on the request it was given.
+	route := r.Pattern
+	if route == "" {
+		route = r.URL.Path
+	}
+
+	fields := []zap.Field{
+		zap.String("method", r.Method),
+		zap.String("route", route),
+		zap.String("path", r.URL.Path),
+		zap.Int("status", rw.status),
+		zap.Int64("bytes", rw.bytes),
+		zap.Duration("latency", time.Since(start)),
+		zap.String("remote_ip", c.remoteIP(r)),
+		zap.String("user_agent", r.UserAgent()),
+	}
+	if len(c.RequestHeaders) > 0 {
+		fields = append(fields, zap.Object("request_headers", c.headers(r.Header, c.RequestHeaders)))
+	}
+	if len(c.ResponseHeaders) > 0 {
+		fields = append(fields, zap.Object("response_headers", c.headers(rw.Header(), c.ResponseHeaders)))
+	}
+	if reqBody != nil {
+		fields = append(fields, c.bodyFields("request_body", reqBody)...)
+	}
+	if rw.body != nil {
+		fields = append(fields, c.bodyFields("response_body", rw.body)...)
+	}
+	fields = append(fields, extra...)
+	ce.Write(fields...)
+}
+
+// remoteIP returns the client IP, without port.
+func (c *Config) remoteIP(r *http.Request) string {
+	if c.TrustForwardedFor {
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			first, _, _ := strings.Cut(xff, ",")
+			return strings.TrimSpace(first)
+		}
+		if ip := r.Header.Get("X-Real-IP"); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// headers logs the allow-listed headers, masking sensitive ones.
+func (c *Config) headers(h http.Header, names []string) zapcore.ObjectMarshaler {
+	return zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		for _, name := range names {
+			values := h.Values(name)
+			if len(values) == 0 {
+				continue
+			}
+			value := strings.Join(values, ", ")
+			if c.Redaction.IsSensitiveKey(name) {
+				value = c.Redaction.Mask
+				if value == "" {
+					value = logger.DefaultRedactMask
+				}
+			} else {
+				value = c.Redaction.RedactString(value)
+			}
+			enc.AddString(name, value)
+		}
+		return nil
+	})
+}
+
+// bodyFields logs a redacted body, or its size when it is not text.
+func (c *Config) bodyFields(key string, body *bodyCapture) []zap.Field {
+	data := body.buf.Bytes()
+	if !utf8.Valid(data) {
+		return []zap.Field{zap.Int(key+"_size", body.size)}
+	}
+	fields := []zap.Field{zap.ByteString(key, c.Redaction.RedactJSON(data))}
+	if body.truncated {
+		fields = append(fields, zap.Bool(key+"_truncated", true))
+	}
+	return fields
+}
+
+// bodyCapture keeps the first limit bytes of a body.
+type bodyCapture struct {
+	limit     int
+	buf       bytes.Buffer
+	size      int // bytes seen
+	truncated bool
+}
+
+func (b *bodyCapture) write(p []byte) {
+	b.size += len(p)
+	if room := b.limit - b.buf.Len(); room > 0 {
+		if len(p) > room {
+			p = p[:room]
+			b.truncated = true
+		}
+		b.buf.Write(p)
+	} else if len(p) > 0 {
+		b.truncated = true
+	}
+}
+
+// captureRequestBody reads up to limit bytes of the body for logging and
+// puts them back in front of the rest of the body for the handler.
+func captureRequestBody(r *http.Request, limit int) *bodyCapture {
+	capture := &bodyCapture{limit: limit}
+	head, err := io.ReadAll(io.LimitReader(r.Body, int64(limit)+1))
+	capture.write(head)
+	rest := io.Reader(r.Body)
+	if err != nil {
+		rest = errReader{err}
+	}
+	r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(head), rest), Closer: r.Body}
+	return capture
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+type errReader struct{ err error }
+
+func (e errReader) Read([]byte) (int, error) { return 0, e.err }
+
+// responseWriter records the status, size and, optionally, body of a response.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+	body        *bodyCapture
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	if w.body != nil {
+		w.body.write(p[:n])
+	}
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flush supports streaming handlers that assert http.Flusher.
+func (w *responseWriter) Flush() {
+	w.wroteHeader = true
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack supports handlers that assert http.Hijacker, e.g. websockets.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, errors.ErrUnsupported
+}
@@ -0,0 +1,356 @@
+package httplog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/laziness-coders/go-utils/logger"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTestLogger(t *testing.T) (*logger.Logger, *bytes.Buffer) {
+	t.Helper()
+	out := &bytes.Buffer{}
+	logger.Init(
+		logger.WithLevel(logger.LevelDebug),
+		logger.WithDev(false),
+		logger.WithSink(logger.Sink{Format: logger.FormatJSON, Output: logger.OutputWriter, Writer: out}),
+	)
+	return logger.GetLogger(), out
+}
+
+func decodeLines(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func serve(t *testing.T, handler http.Handler, req *http.Request, opts ...Option) *httptest.ResponseRecorder {
+	t.Helper()
+	mw, err := Middleware(opts...)
+	if err != nil {
+		t.Fatalf("Middleware() error = %v", err)
+	}
+	rec := httptest.NewRecorder()
+	mw(handler).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware_AccessLog(t *testing.T) {
+	l, out := newTestLogger(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		logger.Ctx(r.Context()).Info("loading order")
+		_, _ = w.Write([]byte("order 42"))
+	})
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled,
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/orders/42", nil).WithContext(ctx)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.Header.Set("User-Agent", "curl/8.0")
+
+	rec := serve(t, mux, req, WithLogger(l))
+
+	entries := decodeLines(t, out)
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2: %s", len(entries), out.String())
+	}
+	handler, access := entries[0], entries[1]
+
+	id := rec.Header().Get(DefaultRequestIDHeader)
+	if len(id) != 32 {
+		t.Errorf("generated request ID = %q", id)
+	}
+	if handler["request_id"] != id || handler["trace_id"] != traceID.String() {
+		t.Errorf("handler entry should carry the request ID and trace fields: %v", handler)
+	}
+
+	want := map[string]interface{}{
+		"level":      "INFO",
+		"msg":        "http request",
+		"method":     "GET",
+		"route":      "GET /orders/{id}",
+		"path":       "/orders/42",
+		"status":     float64(200),
+		"bytes":      float64(8),
+		"remote_ip":  "203.0.113.7",
+		"user_agent": "curl/8.0",
+		"request_id": id,
+		"trace_id":   traceID.String(),
+		"span_id":    spanID.String(),
+	}
+	for key, value := range want {
+		if access[key] != value {
+			t.Errorf("%s = %v, want %v", key, access[key], value)
+		}
+	}
+	if _, ok := access["latency"]; !ok {
+		t.Error("latency should be logged")
+	}
+}
+
+func TestMiddleware_RequestID(t *testing.T) {
+	l, _ := newTestLogger(t)
+
+	tests := []struct {
+		name     string
+		incoming string
+		want     string
+	}{
+		{name: "accepted", incoming: "req-123", want: "req-123"},
+		{name: "generated when missing", incoming: "", want: "generated"},
+		{name: "generated when unsafe", incoming: "bad id\nlevel=error", want: "generated"},
+		{name: "generated when too long", incoming: strings.Repeat("a", maxRequestIDLength+1), want: "generated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = RequestID(r.Context())
+			})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set("X-Correlation-ID", tt.incoming)
+			}
+
+			rec := serve(t, handler, req,
+				WithLogger(l),
+				WithRequestIDHeader("X-Correlation-ID"),
+				WithRequestIDGenerator(func() string { return "generated" }),
+			)
+
+			if seen != tt.want || rec.Header().Get("X-Correlation-ID") != tt.want {
+				t.Errorf("request ID = %q, header = %q, want %q", seen, rec.Header().Get("X-Correlation-ID"), tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddleware_StatusLevels(t *testing.T) {
+	l, out := newTestLogger(t)
+
+	tests := []struct {
+		status int
+		want   string
+	}{
+		{http.StatusOK, "INFO"},
+		{http.StatusFound, "INFO"},
+		{http.StatusNotFound, "WARN"},
+		{http.StatusTooManyRequests, "DEBUG"},
+		{http.StatusServiceUnavailable, "ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			out.Reset()
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			})
+			opts := []Option{WithLogger(l)}
+			if tt.status == http.StatusTooManyRequests {
+				opts = append(opts, WithStatusLevel(4, logger.LevelDebug))
+			}
+			serve(t, handler, httptest.NewRequest(http.MethodGet, "/", nil), opts...)
+
+			entries := decodeLines(t, out)
+			if len(entries) != 1 || entries[0]["level"] != tt.want {
+				t.Errorf("entries = %v, want level %s", entries, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddleware_SkipPaths(t *testing.T) {
+	l, out := newTestLogger(t)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	rec := serve(t, handler, httptest.NewRequest(http.MethodGet, "/healthz", nil),
+		WithLogger(l), WithSkipPaths("/healthz"))
+
+	if out.Len() != 0 {
+		t.Errorf("skipped path should not be logged: %s", out.String())
+	}
+	if rec.Header().Get(DefaultRequestIDHeader) == "" {
+		t.Error("skipped path should still get a request ID")
+	}
+}
+
+func TestMiddleware_Headers(t *testing.T) {
+	l, out := newTestLogger(t)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Cookie", "session=abc")
+
+	serve(t, handler, req, WithLogger(l),
+		WithRequestHeaders("authorization", "accept", "x-missing"),
+		WithResponseHeaders("content-type"))
+
+	entry := decodeLines(t, out)[0]
+	reqHeaders := entry["request_headers"].(map[string]interface{})
+	wantReq := map[string]interface{}{"Authorization": "[REDACTED]", "Accept": "application/json"}
+	if len(reqHeaders) != len(wantReq) {
+		t.Errorf("request_headers = %v, want %v", reqHeaders, wantReq)
+	}
+	for k, v := range wantReq {
+		if reqHeaders[k] != v {
+			t.Errorf("request header %s = %v, want %v", k, reqHeaders[k], v)
+		}
+	}
+	respHeaders := entry["response_headers"].(map[string]interface{})
+	if len(respHeaders) != 1 || respHeaders["Content-Type"] != "application/json" {
+		t.Errorf("response_headers = %v", respHeaders)
+	}
+}
+
+func TestMiddleware_Bodies(t *testing.T) {
+	l, out := newTestLogger(t)
+
+	var received string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		_, _ = w.Write([]byte(`{"token":"abc","status":"created","padding":"xxxxxxxxxxxxxxxxxxxx"}`))
+	})
+
+	reqBody := `{"user":"alice","password":"hunter2"}`
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(reqBody))
+	serve(t, handler, req, WithLogger(l), WithRequestBody(1024), WithResponseBody(30))
+
+	if received != reqBody {
+		t.Errorf("handler received %q, want the full body", received)
+	}
+
+	entry := decodeLines(t, out)[0]
+	if entry["request_body"] != `{"password":"[REDACTED]","user":"alice"}` {
+		t.Errorf("request_body = %v", entry["request_body"])
+	}
+	if _, ok := entry["request_body_truncated"]; ok {
+		t.Error("request body should not be truncated")
+	}
+	if entry["response_body"] != `{"token":"abc","status":"creat` || entry["response_body_truncated"] != true {
+		t.Errorf("response_body = %v, truncated = %v", entry["response_body"], entry["response_body_truncated"])
+	}
+	if entry["bytes"] != float64(67) {
+		t.Errorf("bytes = %v, want the full size", entry["bytes"])
+	}
+}
+
+func TestMiddleware_RemoteIP(t *testing.T) {
+	l, out := newTestLogger(t)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name  string
+		trust bool
+		want  string
+	}{
+		{name: "remote address", trust: false, want: "10.0.0.1"},
+		{name: "forwarded for", trust: true, want: "198.51.100.4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out.Reset()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = "10.0.0.1:4000"
+			req.Header.Set("X-Forwarded-For", "198.51.100.4, 10.0.0.1")
+
+			opts := []Option{WithLogger(l)}
+			if tt.trust {
+				opts = append(opts, WithTrustForwardedFor())
+			}
+			serve(t, handler, req, opts...)
+
+			if got := decodeLines(t, out)[0]["remote_ip"]; got != tt.want {
+				t.Errorf("remote_ip = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddleware_Panic(t *testing.T) {
+	l, out := newTestLogger(t)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic should be passed on")
+			}
+		}()
+		serve(t, handler, httptest.NewRequest(http.MethodGet, "/", nil), WithLogger(l))
+	}()
+
+	entry := decodeLines(t, out)[0]
+	if entry["status"] != float64(500) || entry["panic"] != "boom" || entry["level"] != "ERROR" {
+		t.Errorf("unexpected entry: %v", entry)
+	}
+}
+
+func TestMiddleware_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "invalid level", opts: []Option{WithStatusLevel(5, "fatalish")}},
+		{name: "fatal level", opts: []Option{WithStatusLevel(5, "fatal")}},
+		{name: "panic level", opts: []Option{WithStatusLevel(5, "dpanic")}},
+		{name: "invalid class", opts: []Option{WithStatusLevel(6, logger.LevelError)}},
+		{name: "negative limit", opts: []Option{WithRequestBody(-1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Middleware(tt.opts...); err == nil {
+				t.Error("Middleware() should fail")
+			}
+		})
+	}
+}
+
+func TestResponseWriter_Flush(t *testing.T) {
+	l, _ := newTestLogger(t)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("writer should implement http.Flusher")
+		}
+		_, _ = w.Write([]byte("chunk"))
+		flusher.Flush()
+	})
+
+	rec := serve(t, handler, httptest.NewRequest(http.MethodGet, "/", nil), WithLogger(l))
+	if !rec.Flushed {
+		t.Error("flush should reach the underlying writer")
+	}
+}
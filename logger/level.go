@@ -0,0 +1,314 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// levelControl owns the runtime log level shared by a logger and all of its children,
+// including per-name overrides for loggers created with Named.
+type levelControl struct {
+	atom      zap.AtomicLevel
+	overrides atomic.Pointer[levelOverrides] // nil when there are no overrides
+
+	mu       sync.Mutex
+	timer    *time.Timer   // pending revert of a timed override
+	revertTo zapcore.Level // level restored when timer fires
+}
+
+// levelOverrides is an immutable snapshot of the per-name levels.
+// It is replaced as a whole on every change so readers never lock.
+type levelOverrides struct {
+	levels map[string]zapcore.Level
+	min    zapcore.Level // lowest level among levels
+}
+
+func newLevelControl(atom zap.AtomicLevel, overrides map[string]string) *levelControl {
+	c := &levelControl{atom: atom}
+	levels := make(map[string]zapcore.Level, len(overrides))
+	for name, level := range overrides {
+		// Overrides are checked by Config.validate.
+		levels[name], _ = parseLevel(level)
+	}
+	c.storeOverrides(levels)
+	return c
+}
+
+// parseLevel converts one of the Level* constants into a zap level.
+func parseLevel(level string) (zapcore.Level, error) {
+	switch level {
+	case LevelDebug:
+		return zapcore.DebugLevel, nil
+	case LevelInfo:
+		return zapcore.InfoLevel, nil
+	case LevelWarn:
+		return zapcore.WarnLevel, nil
+	case LevelError:
+		return zapcore.ErrorLevel, nil
+	default:
+		return zapcore.InfoLevel, fmt.Errorf("invalid log level: %s", level)
+	}
+}
+
+// level returns the current level name.
+func (c *levelControl) level() string {
+	return c.atom.Level().String()
+}
+
+// set changes the level and cancels any pending timed override.
+func (c *levelControl) set(level zapcore.Level) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stopTimer()
+	c.atom.SetLevel(level)
+}
+
+// reset changes the level, cancelling any pending timed override, and
+// replaces all overrides. Overrides are checked by Config.validate.
+func (c *levelControl) reset(level zapcore.Level, overrides map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	levels := make(map[string]zapcore.Level, len(overrides))
+	for name, override := range overrides {
+		levels[name], _ = parseLevel(override)
+	}
+	c.stopTimer()
+	c.atom.SetLevel(level)
+	c.storeOverrides(levels)
+}
+
+// setFor changes the level and restores the previous one after d.
+// Stacked overrides restore the level that was active before the first one.
+func (c *levelControl) setFor(level zapcore.Level, d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	revertTo := c.atom.Level()
+	if c.timer != nil {
+		revertTo = c.revertTo
+		c.stopTimer()
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(d, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		// A newer set/setFor call owns the level now.
+		if c.timer != timer {
+			return
+		}
+		c.timer = nil
+		c.atom.SetLevel(c.revertTo)
+	})
+	c.timer = timer
+	c.revertTo = revertTo
+	c.atom.SetLevel(level)
+}
+
+// step moves the level by delta within [debug, error] and returns the new level.
+// A negative delta makes the logger more verbose.
+func (c *levelControl) step(delta int) zapcore.Level {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	level := c.atom.Level() + zapcore.Level(delta)
+	if level < zapcore.DebugLevel {
+		level = zapcore.DebugLevel
+	}
+	if level > zapcore.ErrorLevel {
+		level = zapcore.ErrorLevel
+	}
+
+	c.stopTimer()
+	c.atom.SetLevel(level)
+	return level
+}
+
+// levelFor returns the effective level of the named logger. Names are
+// hierarchical: "payments.stripe" falls back to "payments", then to the base level.
+func (c *levelControl) levelFor(name string) zapcore.Level {
+	o := c.overrides.Load()
+	if o == nil || name == "" {
+		return c.atom.Level()
+	}
+	for {
+		if level, ok := o.levels[name]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			return c.atom.Level()
+		}
+		name = name[:i]
+	}
+}
+
+// minLevel returns the lowest level any logger may log at.
+func (c *levelControl) minLevel() zapcore.Level {
+	level := c.atom.Level()
+	if o := c.overrides.Load(); o != nil && o.min < level {
+		return o.min
+	}
+	return level
+}
+
+// setOverride sets (or, when remove is true, deletes) the level of the named logger.
+func (c *levelControl) setOverride(name string, level zapcore.Level, remove bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	levels := make(map[string]zapcore.Level)
+	if o := c.overrides.Load(); o != nil {
+		for k, v := range o.levels {
+			levels[k] = v
+		}
+	}
+	if remove {
+		delete(levels, name)
+	} else {
+		levels[name] = level
+	}
+	c.storeOverrides(levels)
+}
+
+func (c *levelControl) storeOverrides(levels map[string]zapcore.Level) {
+	if len(levels) == 0 {
+		c.overrides.Store(nil)
+		return
+	}
+	o := &levelOverrides{levels: levels, min: zapcore.FatalLevel}
+	for _, level := range levels {
+		if level < o.min {
+			o.min = level
+		}
+	}
+	c.overrides.Store(o)
+}
+
+// overrideNames returns the current overrides as level names.
+func (c *levelControl) overrideNames() map[string]string {
+	o := c.overrides.Load()
+	if o == nil {
+		return map[string]string{}
+	}
+	names := make(map[string]string, len(o.levels))
+	for name, level := range o.levels {
+		names[name] = level.String()
+	}
+	return names
+}
+
+func (c *levelControl) stopTimer() {
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+}
+
+// SetLevel changes the level of the logger and every logger derived from it.
+func (l *Logger) SetLevel(level string) error {
+	if l.level == nil {
+		return fmt.Errorf("logger has no level control")
+	}
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.level.set(lvl)
+	return nil
+}
+
+// SetLevelFor changes the level for the given duration, then restores the previous level.
+// Useful for temporarily enabling debug logs in production.
+func (l *Logger) SetLevelFor(level string, d time.Duration) error {
+	if l.level == nil {
+		return fmt.Errorf("logger has no level control")
+	}
+	if d <= 0 {
+		return fmt.Errorf("invalid level override duration: %s", d)
+	}
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.level.setFor(lvl, d)
+	return nil
+}
+
+// levelName returns the current level name of the logger (debug, info, warn, error).
+func (l *Logger) levelName() string {
+	if l.level == nil {
+		return l.Logger.Level().String()
+	}
+	return l.level.level()
+}
+
+// SetLevelOverride sets the level of the logger with the given name and of its
+// descendants, e.g. "payments" also covers "payments.stripe" unless that has
+// its own override. Existing loggers pick up the change immediately.
+func (l *Logger) SetLevelOverride(name, level string) error {
+	if l.level == nil {
+		return fmt.Errorf("logger has no level control")
+	}
+	if name == "" {
+		return fmt.Errorf("logger name is required")
+	}
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.level.setOverride(name, lvl, false)
+	return nil
+}
+
+// RemoveLevelOverride removes the override of the named logger, which then
+// falls back to its parent's level.
+func (l *Logger) RemoveLevelOverride(name string) {
+	if l.level != nil {
+		l.level.setOverride(name, zapcore.InfoLevel, true)
+	}
+}
+
+// LevelOverrides returns the current per-name levels.
+func (l *Logger) LevelOverrides() map[string]string {
+	if l.level == nil {
+		return map[string]string{}
+	}
+	return l.level.overrideNames()
+}
+
+// parseLevelSpec splits a spec like "info,payments=debug,payments.stripe=warn"
+// into its base level and per-name overrides. The base level may be omitted.
+func parseLevelSpec(spec string) (string, map[string]string, error) {
+	var base string
+	overrides := map[string]string{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, level, ok := strings.Cut(part, "=")
+		if !ok {
+			if base != "" {
+				return "", nil, fmt.Errorf("invalid level spec %q: multiple base levels", spec)
+			}
+			base = part
+			continue
+		}
+		name, level = strings.TrimSpace(name), strings.TrimSpace(level)
+		if name == "" {
+			return "", nil, fmt.Errorf("invalid level spec %q: empty logger name", spec)
+		}
+		overrides[name] = level
+	}
+	return base, overrides, nil
+}
@@ -0,0 +1,36 @@
+package logger
+
+import "go.uber.org/zap/zapcore"
+
+// levelCore filters entries by the effective level of the logger that wrote
+// them, so Named loggers honor their overrides. The wrapped core is expected
+// to accept every level the levelControl may allow.
+type levelCore struct {
+	zapcore.Core
+	level *levelControl
+}
+
+func newLevelCore(core zapcore.Core, level *levelControl) zapcore.Core {
+	return &levelCore{Core: core, level: level}
+}
+
+// Enabled reports whether any logger may log at lvl; the per-name decision is made in Check.
+func (c *levelCore) Enabled(lvl zapcore.Level) bool {
+	return lvl >= c.level.minLevel() && c.Core.Enabled(lvl)
+}
+
+// Level implements zapcore.LevelEnabler's optional Level method.
+func (c *levelCore) Level() zapcore.Level {
+	return c.level.minLevel()
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level < c.level.levelFor(ent.LoggerName) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// levelPayload is the body accepted and returned by the level handler.
+type levelPayload struct {
+	Level     string            `json:"level"`
+	Duration  string            `json:"duration,omitempty"`  // optional timed override, e.g. "10m"
+	Overrides map[string]string `json:"overrides,omitempty"` // per-name levels, read-only
+}
+
+type levelErrorPayload struct {
+	Error string `json:"error"`
+}
+
+// LevelHandler returns an http.Handler to inspect and change the logger level.
+//
+//	GET  returns the current level: {"level":"info","overrides":{"payments":"debug"}}
+//	PUT  changes it, either with a JSON body {"level":"debug","duration":"10m"}
+//	     or form values level=debug&duration=10m
+//
+// The optional duration turns the change into a timed override that reverts
+// to the previous level once it elapses.
+func (l *Logger) LevelHandler() http.Handler {
+	return http.HandlerFunc(l.serveLevel)
+}
+
+func (l *Logger) serveLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeLevelJSON(w, http.StatusOK, levelPayload{Level: l.levelName(), Overrides: l.LevelOverrides()})
+	case http.MethodPut:
+		req, err := decodeLevelRequest(r)
+		if err != nil {
+			writeLevelJSON(w, http.StatusBadRequest, levelErrorPayload{Error: err.Error()})
+			return
+		}
+		if err := l.applyLevelRequest(req); err != nil {
+			writeLevelJSON(w, http.StatusBadRequest, levelErrorPayload{Error: err.Error()})
+			return
+		}
+		writeLevelJSON(w, http.StatusOK, levelPayload{Level: l.levelName(), Duration: req.Duration})
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		writeLevelJSON(w, http.StatusMethodNotAllowed, levelErrorPayload{
+			Error: fmt.Sprintf("method %s not allowed", r.Method),
+		})
+	}
+}
+
+func (l *Logger) applyLevelRequest(req levelPayload) error {
+	if req.Duration == "" {
+		return l.SetLevel(req.Level)
+	}
+	d, err := time.ParseDuration(req.Duration)
+	if err != nil {
+		return fmt.Errorf("invalid duration: %s", req.Duration)
+	}
+	return l.SetLevelFor(req.Level, d)
+}
+
+func decodeLevelRequest(r *http.Request) (levelPayload, error) {
+	var req levelPayload
+	if r.Header.Get("Content-Type") == "application/x-www-form-urlencoded" {
+		if err := r.ParseForm(); err != nil {
+			return req, fmt.Errorf("invalid form body: %w", err)
+		}
+		req.Level = r.Form.Get("level")
+		req.Duration = r.Form.Get("duration")
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return req, fmt.Errorf("invalid json body: %w", err)
+	}
+	if req.Level == "" {
+		return req, fmt.Errorf("level is required")
+	}
+	return req, nil
+}
+
+func writeLevelJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,45 @@
+//go:build !windows
+
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// HandleLevelSignals steps the logger level when the process receives a signal:
+// SIGUSR1 makes it more verbose (e.g. info -> debug), SIGUSR2 less verbose
+// (e.g. info -> warn). The returned func stops the signal handling.
+func (l *Logger) HandleLevelSignals() (stop func()) {
+	if l.level == nil {
+		return func() {}
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigCh, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	go func() {
+		for {
+			select {
+			case sig := <-sigCh:
+				delta := 1
+				if sig == syscall.SIGUSR1 {
+					delta = -1
+				}
+				level := l.level.step(delta)
+				l.Info("log level changed by signal", zap.Stringer("signal", sig), zap.Stringer("level", level))
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigCh)
+		close(done)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build !windows
+
+package logger
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestLogger_HandleLevelSignals(t *testing.T) {
+	logger, _ := newLogger(WithLevel(LevelInfo), WithFormat(FormatJSON))
+	stop := logger.HandleLevelSignals()
+	defer stop()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("failed to send SIGUSR1: %v", err)
+	}
+	waitForLevel(t, logger, LevelDebug)
+
+	for i := 0; i < 5; i++ {
+		if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR2); err != nil {
+			t.Fatalf("failed to send SIGUSR2: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Stepping stops at error.
+	waitForLevel(t, logger, LevelError)
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package logger
+
+// HandleLevelSignals is a no-op on Windows, which has no SIGUSR1/SIGUSR2.
+func (l *Logger) HandleLevelSignals() (stop func()) {
+	return func() {}
+}
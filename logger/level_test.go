@@ -0,0 +1,279 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogger_SetLevel(t *testing.T) {
+	logger, err := newLogger(WithLevel(LevelInfo), WithFormat(FormatJSON))
+	if err != nil {
+		t.Fatalf("newLogger() error = %v", err)
+	}
+
+	if err := logger.SetLevel(LevelError); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	if got := logger.levelName(); got != LevelError {
+		t.Errorf("level = %s, want %s", got, LevelError)
+	}
+
+	// Children share the level with their parent.
+	child := logger.With()
+	if err := child.SetLevel(LevelDebug); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	if got := logger.levelName(); got != LevelDebug {
+		t.Errorf("parent level = %s, want %s", got, LevelDebug)
+	}
+
+	if err := logger.SetLevel("verbose"); err == nil {
+		t.Error("SetLevel() with invalid level should return an error")
+	}
+}
+
+func TestLogger_SetLevelFor(t *testing.T) {
+	logger, _ := newLogger(WithLevel(LevelInfo), WithFormat(FormatJSON))
+
+	if err := logger.SetLevelFor(LevelDebug, 20*time.Millisecond); err != nil {
+		t.Fatalf("SetLevelFor() error = %v", err)
+	}
+	// Stacked overrides restore the original level.
+	if err := logger.SetLevelFor(LevelWarn, 30*time.Millisecond); err != nil {
+		t.Fatalf("SetLevelFor() error = %v", err)
+	}
+	if got := logger.levelName(); got != LevelWarn {
+		t.Errorf("level = %s, want %s", got, LevelWarn)
+	}
+
+	waitForLevel(t, logger, LevelInfo)
+
+	// A plain SetLevel cancels a pending revert.
+	_ = logger.SetLevelFor(LevelDebug, 20*time.Millisecond)
+	_ = logger.SetLevel(LevelError)
+	time.Sleep(50 * time.Millisecond)
+	if got := logger.levelName(); got != LevelError {
+		t.Errorf("level = %s, want %s", got, LevelError)
+	}
+
+	if err := logger.SetLevelFor(LevelDebug, 0); err == nil {
+		t.Error("SetLevelFor() with zero duration should return an error")
+	}
+}
+
+func TestLogger_LevelHandler(t *testing.T) {
+	logger, _ := newLogger(WithLevel(LevelInfo), WithFormat(FormatJSON))
+	handler := logger.LevelHandler()
+
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		wantStatus  int
+		wantLevel   string
+	}{
+		{name: "get", method: http.MethodGet, wantStatus: http.StatusOK, wantLevel: LevelInfo},
+		{name: "put json", method: http.MethodPut, body: `{"level":"warn"}`, wantStatus: http.StatusOK, wantLevel: LevelWarn},
+		{
+			name:        "put form",
+			method:      http.MethodPut,
+			contentType: "application/x-www-form-urlencoded",
+			body:        "level=error",
+			wantStatus:  http.StatusOK,
+			wantLevel:   LevelError,
+		},
+		{name: "put invalid level", method: http.MethodPut, body: `{"level":"loud"}`, wantStatus: http.StatusBadRequest, wantLevel: LevelError},
+		{name: "put missing level", method: http.MethodPut, body: `{}`, wantStatus: http.StatusBadRequest, wantLevel: LevelError},
+		{name: "put invalid duration", method: http.MethodPut, body: `{"level":"debug","duration":"soon"}`, wantStatus: http.StatusBadRequest, wantLevel: LevelError},
+		{name: "post", method: http.MethodPost, wantStatus: http.StatusMethodNotAllowed, wantLevel: LevelError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/log/level", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if got := logger.levelName(); got != tt.wantLevel {
+				t.Errorf("level = %s, want %s", got, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestLogger_LevelHandlerTimedOverride(t *testing.T) {
+	logger, _ := newLogger(WithLevel(LevelInfo), WithFormat(FormatJSON))
+
+	req := httptest.NewRequest(http.MethodPut, "/log/level", strings.NewReader(`{"level":"debug","duration":"20ms"}`))
+	rec := httptest.NewRecorder()
+	logger.LevelHandler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if !strings.Contains(rec.Body.String(), `"level":"debug"`) {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+	waitForLevel(t, logger, LevelInfo)
+}
+
+func TestGlobalSetLevel(t *testing.T) {
+	Init(WithLevel(LevelInfo), WithFormat(FormatJSON))
+
+	if err := SetLevel(LevelWarn); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	if got := Level(); got != LevelWarn {
+		t.Errorf("Level() = %s, want %s", got, LevelWarn)
+	}
+
+	// Children created through the global With follow the global level.
+	child := With()
+	if err := SetLevel(LevelDebug); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	if !child.Core().Enabled(zapcore.DebugLevel) {
+		t.Error("child logger should follow the global level")
+	}
+}
+
+func waitForLevel(t *testing.T, logger *Logger, want string) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if logger.levelName() == want {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Errorf("level = %s, want %s", logger.levelName(), want)
+}
+
+// newObservedLogger builds a Logger from the given options that records its entries.
+func newObservedLogger(t *testing.T, opts ...Option) (*Logger, *observer.ObservedLogs) {
+	t.Helper()
+	cfg, err := newConfig(opts...)
+	if err != nil {
+		t.Fatalf("newConfig() error = %v", err)
+	}
+	core, logs := observer.New(zapcore.DebugLevel)
+	level := newLevelControl(cfg.AtomicLevel, cfg.LevelOverrides)
+	return &Logger{Logger: zap.New(newLevelCore(core, level)), level: level}, logs
+}
+
+func TestLogger_NamedLevelOverrides(t *testing.T) {
+	logger, logs := newObservedLogger(t,
+		WithLevel("info,payments=debug"),
+		WithLevelOverride("payments.stripe", LevelError),
+	)
+
+	logger.Debug("root debug")
+	logger.Named("payments").Debug("payments debug")
+	logger.Named("payments").Named("paypal").Debug("paypal debug")
+	logger.Named("payments").Named("stripe").Warn("stripe warn")
+	logger.Named("payments").Named("stripe").Error("stripe error")
+	logger.Named("orders").Debug("orders debug")
+	logger.Named("orders").Info("orders info")
+
+	want := []string{"payments debug", "paypal debug", "stripe error", "orders info"}
+	assertMessages(t, logs, want)
+}
+
+func TestLogger_SetLevelOverrideAtRuntime(t *testing.T) {
+	logger, logs := newObservedLogger(t, WithLevel(LevelInfo))
+	payments := logger.Named("payments")
+	traced := payments.Trace(context.Background())
+
+	payments.Debug("before")
+	if err := logger.SetLevelOverride("payments", LevelDebug); err != nil {
+		t.Fatalf("SetLevelOverride() error = %v", err)
+	}
+	payments.Debug("after")
+	traced.Debug("traced")
+	logger.Debug("root")
+
+	logger.RemoveLevelOverride("payments")
+	payments.Debug("removed")
+
+	assertMessages(t, logs, []string{"after", "traced"})
+
+	if err := logger.SetLevelOverride("payments", "loud"); err == nil {
+		t.Error("SetLevelOverride() with invalid level should return an error")
+	}
+	if err := logger.SetLevelOverride("", LevelDebug); err == nil {
+		t.Error("SetLevelOverride() without name should return an error")
+	}
+}
+
+func TestConfig_LevelSpec(t *testing.T) {
+	tests := []struct {
+		name          string
+		level         string
+		wantLevel     string
+		wantOverrides map[string]string
+		wantErr       bool
+	}{
+		{name: "plain level", level: LevelWarn, wantLevel: LevelWarn, wantOverrides: nil},
+		{
+			name:          "base and overrides",
+			level:         "info, payments=debug ,payments.stripe=warn",
+			wantLevel:     LevelInfo,
+			wantOverrides: map[string]string{"payments": LevelDebug, "payments.stripe": LevelWarn},
+		},
+		{
+			name:          "overrides only",
+			level:         "payments=debug",
+			wantLevel:     LevelDebug, // default
+			wantOverrides: map[string]string{"payments": LevelDebug},
+		},
+		{name: "two base levels", level: "info,warn", wantErr: true},
+		{name: "empty name", level: "info,=debug", wantErr: true},
+		{name: "invalid override level", level: "info,payments=loud", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := newConfig(WithLevel(tt.level))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if cfg.Level != tt.wantLevel {
+				t.Errorf("Level = %s, want %s", cfg.Level, tt.wantLevel)
+			}
+			if !cmp.Equal(cfg.LevelOverrides, tt.wantOverrides, cmpopts.EquateEmpty()) {
+				t.Errorf("LevelOverrides mismatch:\n%s", cmp.Diff(tt.wantOverrides, cfg.LevelOverrides))
+			}
+		})
+	}
+}
+
+func assertMessages(t *testing.T, logs *observer.ObservedLogs, want []string) {
+	t.Helper()
+	var got []string
+	for _, entry := range logs.All() {
+		got = append(got, entry.Message)
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("logged messages mismatch:\n%s", cmp.Diff(want, got))
+	}
+}
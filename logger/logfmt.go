@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var encoderPool = buffer.NewPool()
+
+// logfmtEncoder writes entries as logfmt lines:
+//
+//	ts=2026-10-17T12:00:00.000Z level=info logger=payments msg="charge failed" order_id=42
+//
+// Nested objects are flattened into dotted keys, arrays are written as JSON.
+type logfmtEncoder struct {
+	*fieldCollector
+}
+
+// newLogfmtEncoder creates a logfmt encoder using the keys and the time,
+// level, caller, name and duration encoders of cfg.
+func newLogfmtEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return &logfmtEncoder{fieldCollector: &fieldCollector{cfg: &cfg}}
+}
+
+// logfmtEncoderConfig is zap's production configuration with ISO8601 times
+// and lowercase levels.
+func logfmtEncoderConfig() zapcore.EncoderConfig {
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.EncodeTime = zapcore.ISO8601TimeEncoder
+	cfg.EncodeLevel = zapcore.LowercaseLevelEncoder
+	cfg.EncodeDuration = zapcore.StringDurationEncoder
+	return cfg
+}
+
+func (e *logfmtEncoder) Clone() zapcore.Encoder {
+	return &logfmtEncoder{fieldCollector: e.fieldCollector.clone()}
+}
+
+func (e *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := e.fieldCollector.clone()
+	for i := range fields {
+		fields[i].AddTo(final)
+	}
+
+	cfg := e.cfg
+	buf := encoderPool.Get()
+	writePair := func(key, value string) {
+		if buf.Len() > 0 {
+			buf.AppendByte(' ')
+		}
+		buf.AppendString(logfmtKey(key))
+		buf.AppendByte('=')
+		buf.AppendString(logfmtValue(value))
+	}
+
+	if cfg.TimeKey != "" && cfg.EncodeTime != nil {
+		writePair(cfg.TimeKey, encodePrimitive(func(enc zapcore.PrimitiveArrayEncoder) { cfg.EncodeTime(ent.Time, enc) }))
+	}
+	if cfg.LevelKey != "" && cfg.EncodeLevel != nil {
+		writePair(cfg.LevelKey, encodePrimitive(func(enc zapcore.PrimitiveArrayEncoder) { cfg.EncodeLevel(ent.Level, enc) }))
+	}
+	if cfg.NameKey != "" && ent.LoggerName != "" {
+		writePair(cfg.NameKey, encodeName(cfg, ent.LoggerName))
+	}
+	if ent.Caller.Defined {
+		if cfg.CallerKey != "" && cfg.EncodeCaller != nil {
+			writePair(cfg.CallerKey, encodePrimitive(func(enc zapcore.PrimitiveArrayEncoder) { cfg.EncodeCaller(ent.Caller, enc) }))
+		}
+		if cfg.FunctionKey != "" {
+			writePair(cfg.FunctionKey, ent.Caller.Function)
+		}
+	}
+	if cfg.MessageKey != "" {
+		writePair(cfg.MessageKey, ent.Message)
+	}
+	for _, f := range final.fields {
+		writePair(f.key, f.value)
+	}
+	if cfg.StacktraceKey != "" && ent.Stack != "" {
+		writePair(cfg.StacktraceKey, ent.Stack)
+	}
+	buf.AppendString(lineEnding(cfg))
+	return buf, nil
+}
+
+// encodeName renders a logger name with cfg's name encoder, if any.
+func encodeName(cfg *zapcore.EncoderConfig, name string) string {
+	if cfg.EncodeName == nil {
+		return name
+	}
+	return encodePrimitive(func(enc zapcore.PrimitiveArrayEncoder) { cfg.EncodeName(name, enc) })
+}
+
+func lineEnding(cfg *zapcore.EncoderConfig) string {
+	if cfg.LineEnding == "" {
+		return zapcore.DefaultLineEnding
+	}
+	return cfg.LineEnding
+}
@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// stackError is an error whose %+v verb prints a multi-line stack, like the
+// errors of github.com/pkg/errors.
+type stackError struct{ msg string }
+
+func (e stackError) Error() string { return e.msg }
+
+func (e stackError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprintf(s, "%s\nmain.charge\n\t/app/charge.go:40", e.msg)
+		return
+	}
+	fmt.Fprint(s, e.msg)
+}
+
+// testEntry returns an entry with a fixed time and caller.
+func testEntry(level zapcore.Level, msg string) zapcore.Entry {
+	return zapcore.Entry{
+		Level:      level,
+		Time:       newTestClock().Now(),
+		LoggerName: "payments",
+		Message:    msg,
+		Caller:     zapcore.NewEntryCaller(0, "/app/logger/charge.go", 42, true),
+	}
+}
+
+type testObject map[string]string
+
+func (o testObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, key := range []string{"method", "path"} {
+		if value, ok := o[key]; ok {
+			enc.AddString(key, value)
+		}
+	}
+	return nil
+}
+
+func TestLogfmtEncoder(t *testing.T) {
+	tests := []struct {
+		name   string
+		entry  zapcore.Entry
+		fields []zapcore.Field
+		want   string
+	}{
+		{
+			name:   "fields",
+			entry:  testEntry(zapcore.InfoLevel, "charge failed"),
+			fields: []zapcore.Field{zap.Int("order_id", 42), zap.Bool("retry", true), zap.Duration("took", 1500*time.Millisecond)},
+			want:   `ts=2026-10-17T12:00:00.000Z level=info logger=payments caller=logger/charge.go:42 msg="charge failed" order_id=42 retry=true took=1.5s` + "\n",
+		},
+		{
+			name:   "quoting",
+			entry:  testEntry(zapcore.WarnLevel, "ok"),
+			fields: []zapcore.Field{zap.String("empty", ""), zap.String("query", `a="b"`), zap.String("line", "one\ntwo"), zap.String("bad key", "x")},
+			want:   `ts=2026-10-17T12:00:00.000Z level=warn logger=payments caller=logger/charge.go:42 msg=ok empty="" query="a=\"b\"" line="one\ntwo" bad_key=x` + "\n",
+		},
+		{
+			name:  "objects, namespaces and arrays",
+			entry: testEntry(zapcore.DebugLevel, "request"),
+			fields: []zapcore.Field{
+				zap.Object("http", testObject{"method": "GET", "path": "/orders"}),
+				zap.Object("none", testObject{}),
+				zap.Strings("tags", []string{"a", "b"}),
+				zap.Namespace("db"),
+				zap.Int("rows", 3),
+			},
+			want: `ts=2026-10-17T12:00:00.000Z level=debug logger=payments caller=logger/charge.go:42 msg=request http.method=GET http.path=/orders none={} tags="[\"a\",\"b\"]" db.rows=3` + "\n",
+		},
+		{
+			name: "error and stacktrace",
+			entry: func() zapcore.Entry {
+				e := testEntry(zapcore.ErrorLevel, "charge failed")
+				e.Stack = "main.charge\n\t/app/charge.go:40"
+				return e
+			}(),
+			fields: []zapcore.Field{zap.Error(errors.New("card declined"))},
+			want:   `ts=2026-10-17T12:00:00.000Z level=error logger=payments caller=logger/charge.go:42 msg="charge failed" error="card declined" stacktrace="main.charge\n\t/app/charge.go:40"` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, err := newLogfmtEncoder(logfmtEncoderConfig()).EncodeEntry(tt.entry, tt.fields)
+			if err != nil {
+				t.Fatalf("EncodeEntry() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("EncodeEntry() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogfmtEncoder_With(t *testing.T) {
+	enc := newLogfmtEncoder(logfmtEncoderConfig())
+	enc.AddString("service", "orders")
+	child := enc.Clone()
+	child.AddInt("attempt", 2)
+
+	buf, err := enc.EncodeEntry(testEntry(zapcore.InfoLevel, "parent"), nil)
+	if err != nil {
+		t.Fatalf("EncodeEntry() error = %v", err)
+	}
+	if want := "ts=2026-10-17T12:00:00.000Z level=info logger=payments caller=logger/charge.go:42 msg=parent service=orders\n"; buf.String() != want {
+		t.Errorf("parent =\n%s\nwant\n%s", buf.String(), want)
+	}
+	buf, err = child.EncodeEntry(testEntry(zapcore.InfoLevel, "child"), nil)
+	if err != nil {
+		t.Fatalf("EncodeEntry() error = %v", err)
+	}
+	if want := "ts=2026-10-17T12:00:00.000Z level=info logger=payments caller=logger/charge.go:42 msg=child service=orders attempt=2\n"; buf.String() != want {
+		t.Errorf("child =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+// TestLogger_FormatHonoredInDev checks that IsDev no longer replaces the
+// selected format with zap's development console layout.
+func TestLogger_FormatHonoredInDev(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: FormatJSON, want: `{"level":"INFO","ts":"2026-10-17T12:00:00.000Z","logger":"payments","msg":"charge failed","order_id":42}` + "\n"},
+		{format: FormatText, want: "2026-10-17T12:00:00.000Z\tINFO\tpayments\tcharge failed\t{\"order_id\": 42}\n"},
+		{format: FormatLogfmt, want: "ts=2026-10-17T12:00:00.000Z level=info logger=payments msg=\"charge failed\" order_id=42\n"},
+		{format: FormatPretty, want: "12:00:00.000 INFO  payments charge failed order_id=42\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "1")
+			out := &bytes.Buffer{}
+			logger, err := newLogger(
+				WithDev(true),
+				WithClock(newTestClock()),
+				WithSink(Sink{Format: tt.format, Output: OutputWriter, Writer: out}),
+			)
+			if err != nil {
+				t.Fatalf("newLogger() error = %v", err)
+			}
+
+			logger.WithOptions(zap.WithCaller(false)).Named("payments").Info("charge failed", zap.Int("order_id", 42))
+			if got := out.String(); got != tt.want {
+				t.Errorf("output =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
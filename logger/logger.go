@@ -2,112 +2,292 @@ package logger
 
 import (
 	"context"
+	"net/http"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 var (
-	// globalLogger is the global logger instance
-	globalLogger *Logger
+	// globalLogger is the global logger instance. It is never nil: until
+	// Init is called it holds a no-op logger, or the stderr fallback.
+	globalLogger atomic.Pointer[Logger]
+	// initialized is set by Init, so the fallback does not replace it.
+	initialized atomic.Bool
+
+	// configured is the global logger installed by InitFromConfig.
+	configured   *configuredLogger
+	configuredMu sync.Mutex
 )
 
+// configuredLogger is a logger with the configuration it was built from.
+type configuredLogger struct {
+	logger *Logger
+	cfg    *Config
+}
+
+func init() {
+	globalLogger.Store(newNopLogger())
+}
+
+// newNopLogger creates a logger discarding all entries, whose level can
+// still be read and changed.
+func newNopLogger() *Logger {
+	return &Logger{
+		Logger:  zap.NewNop(),
+		level:   newLevelControl(zap.NewAtomicLevelAt(zapcore.InfoLevel), nil),
+		outputs: &outputs{},
+	}
+}
+
 // GetLogger returns the global logger instance.
-// It panics if the logger is not initialized.
+// Before Init, it returns a no-op logger, or the stderr fallback.
 func GetLogger() *Logger {
-	if globalLogger == nil {
-		panic("global logger is not initialized")
-	}
-	return globalLogger
+	return globalLogger.Load()
 }
 
 // Init initializes the global logger with functional options.
-// This should be called once at application startup.
+// This should be called once at application startup. Calling it again
+// replaces the global logger, then flushes the previous one, which stays
+// open for the goroutines and children still using it: close it once they
+// are done.
+//
+//	previous := logger.GetLogger()
+//	logger.Init(opts...)
+//	defer previous.Close()
+//
+// It is safe to call while other goroutines log.
 func Init(opts ...Option) {
 	logger, err := newLogger(opts...)
 	if err != nil {
 		panic(err)
 	}
-	globalLogger = logger
+	install(logger)
+}
+
+// install makes logger the global logger, then flushes the previous one. The
+// previous logger is not closed: it may still be in use.
+func install(logger *Logger) {
+	initialized.Store(true)
+	if previous := globalLogger.Swap(logger); previous != nil {
+		_ = previous.Sync()
+	}
+}
+
+// swapGlobal makes logger the global logger, with the given initialized
+// state and no InitFromConfig configuration, until restore is called.
+func swapGlobal(logger *Logger, init bool) (restore func()) {
+	previous := globalLogger.Swap(logger)
+	wasInitialized := initialized.Swap(init)
+	configuredMu.Lock()
+	wasConfigured := configured
+	configured = nil
+	configuredMu.Unlock()
+	return func() {
+		globalLogger.Store(previous)
+		initialized.Store(wasInitialized)
+		configuredMu.Lock()
+		configured = wasConfigured
+		configuredMu.Unlock()
+	}
+}
+
+// InitFromConfig initializes the global logger from cfg, e.g. decoded by
+// configs.ConfigLoader, and reports all invalid fields at once.
+// Calling it again with only Level or LevelOverrides changed updates the
+// levels of the current global logger in place, through its AtomicLevel;
+// other changes replace it as Init does.
+// Usage:
+//
+//	var cfg struct {
+//		Logger logger.Config `mapstructure:"LOGGER"`
+//	}
+//	_ = configs.New(&cfg).Load(env, "./configs")
+//	if err := logger.InitFromConfig(cfg.Logger); err != nil {
+//		log.Fatal(err)
+//	}
+func InitFromConfig(cfg Config) error {
+	built, err := newConfig(WithConfig(cfg))
+	if err != nil {
+		return err
+	}
+
+	configuredMu.Lock()
+	defer configuredMu.Unlock()
+	if configured != nil && configured.logger == GetLogger() &&
+		reflect.DeepEqual(configured.cfg.withoutLevels(), built.withoutLevels()) {
+		configured.logger.level.reset(built.AtomicLevel.Level(), built.LevelOverrides)
+		configured.cfg = built
+		return nil
+	}
+
+	logger := newLoggerFromConfig(built)
+	install(logger)
+	configured = &configuredLogger{logger: logger, cfg: built}
+	return nil
+}
+
+// UseStderrFallback makes the global logger write entries at or above level
+// to stderr, as text, until Init is called. It does nothing after Init.
+// Use it in main packages whose imports may log before the logger is set up.
+func UseStderrFallback(level string) error {
+	fallback, err := newLogger(
+		WithLevel(level),
+		WithDev(false),
+		WithSink(Sink{Format: FormatText, Output: OutputStderr}),
+	)
+	if err != nil {
+		return err
+	}
+	for {
+		current := globalLogger.Load()
+		if initialized.Load() {
+			return fallback.Close()
+		}
+		if globalLogger.CompareAndSwap(current, fallback) {
+			return current.Close()
+		}
+	}
+}
+
+// Sync flushes the buffered entries of the global logger.
+// Call it, or Close, before the application exits.
+func Sync() error {
+	return GetLogger().Sync()
+}
+
+// Close flushes and releases the outputs of the global logger, e.g. on SIGTERM:
+//
+//	defer logger.Close()
+func Close() error {
+	return GetLogger().Close()
 }
 
 func Info(msg string, fields ...zap.Field) {
-	globalLogger.Info(msg, fields...)
+	GetLogger().Info(msg, fields...)
 }
 
 func Debug(msg string, fields ...zap.Field) {
-	globalLogger.Debug(msg, fields...)
+	GetLogger().Debug(msg, fields...)
 }
 
 func Warn(msg string, fields ...zap.Field) {
-	globalLogger.Warn(msg, fields...)
+	GetLogger().Warn(msg, fields...)
 }
 
 func Error(msg string, fields ...zap.Field) {
-	globalLogger.Error(msg, fields...)
+	GetLogger().Error(msg, fields...)
 }
 
 func Fatal(msg string, fields ...zap.Field) {
-	globalLogger.Fatal(msg, fields...)
+	GetLogger().Fatal(msg, fields...)
 }
 
 func Panic(msg string, fields ...zap.Field) {
-	globalLogger.Panic(msg, fields...)
+	GetLogger().Panic(msg, fields...)
 }
 
 // Trace extracts tracing fields from context using the global logger.
 func Trace(ctx context.Context) *Logger {
-	if globalLogger != nil {
-		return globalLogger.Trace(ctx)
-	}
-	panic("global logger is not initialized")
+	return GetLogger().Trace(ctx)
 }
 
 // Sugar returns a sugared logger from the global logger.
 func Sugar() *SugaredLogger {
-	if globalLogger != nil {
-		return globalLogger.Sugar()
-	}
-	panic("global logger is not initialized")
+	return GetLogger().Sugar()
 }
 
 // With creates a child logger with additional fields using the global logger.
 func With(fields ...zap.Field) *Logger {
-	if globalLogger != nil {
-		return &Logger{
-			Logger: globalLogger.With(fields...),
-		}
-	}
-	panic("global logger is not initialized")
+	return GetLogger().With(fields...)
+}
+
+// SetLevel changes the level of the global logger at runtime.
+func SetLevel(level string) error {
+	return GetLogger().SetLevel(level)
+}
+
+// SetLevelFor changes the level of the global logger for the given duration,
+// then restores the previous level.
+func SetLevelFor(level string, d time.Duration) error {
+	return GetLogger().SetLevelFor(level, d)
+}
+
+// Level returns the current level of the global logger.
+func Level() string {
+	return GetLogger().levelName()
+}
+
+// Named returns a named child of the global logger.
+func Named(name string) *Logger {
+	return GetLogger().Named(name)
+}
+
+// SetLevelOverride sets the level of the named loggers of the global logger.
+func SetLevelOverride(name, level string) error {
+	return GetLogger().SetLevelOverride(name, level)
+}
+
+// RemoveLevelOverride removes a level override from the global logger.
+func RemoveLevelOverride(name string) {
+	GetLogger().RemoveLevelOverride(name)
+}
+
+// LevelHandler returns an http.Handler that reads (GET) and changes (PUT) the
+// level of the global logger. The logger is resolved on every request, so the
+// handler can be mounted before Init is called.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		GetLogger().LevelHandler().ServeHTTP(w, r)
+	})
+}
+
+// HandleLevelSignals steps the level of the global logger on SIGUSR1/SIGUSR2.
+// See Logger.HandleLevelSignals.
+func HandleLevelSignals() (stop func()) {
+	return GetLogger().HandleLevelSignals()
 }
 
 // Package-level print functions
 func Print(args ...interface{}) {
-	globalLogger.Print(args...)
+	GetLogger().Print(args...)
 }
 
 func Println(args ...interface{}) {
-	globalLogger.Println(args...)
+	GetLogger().Println(args...)
 }
 
 func Printf(format string, args ...interface{}) {
-	globalLogger.Printf(format, args...)
+	GetLogger().Printf(format, args...)
 }
 
 func Debugf(format string, args ...interface{}) {
-	globalLogger.Debugf(format, args...)
+	GetLogger().Debugf(format, args...)
 }
 
 func Infof(format string, args ...interface{}) {
-	globalLogger.Infof(format, args...)
+	GetLogger().Infof(format, args...)
 }
 
 func Warnf(format string, args ...interface{}) {
-	globalLogger.Warnf(format, args...)
+	GetLogger().Warnf(format, args...)
 }
 
 func Errorf(format string, args ...interface{}) {
-	globalLogger.Errorf(format, args...)
+	GetLogger().Errorf(format, args...)
 }
 
 func Fatalf(format string, args ...interface{}) {
-	globalLogger.Fatalf(format, args...)
+	GetLogger().Fatalf(format, args...)
+}
+
+// RedirectStdLog sends the standard library's log output through the global
+// logger at level, see Logger.RedirectStdLog. It is bound to the current global
+// logger, so call it after Init.
+func RedirectStdLog(level string) (restore func(), err error) {
+	return GetLogger().RedirectStdLog(level)
 }
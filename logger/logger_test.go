@@ -431,8 +431,8 @@ func TestNewWithOptions(t *testing.T) {
 }
 
 func TestPredefinedOptions(t *testing.T) {
-	// Test production defaults
-	logger, err := newLogger(WithProductionDefaults())
+	// Test production defaults, writing to a temporary file
+	logger, err := newLogger(WithProductionDefaults(), WithFileOutput(filepath.Join(t.TempDir(), "app.log")))
 	if err != nil {
 		t.Errorf("newLogger(WithProductionDefaults()) error = %v", err)
 		return
@@ -499,8 +499,8 @@ func TestGlobalLogWithoutOptions(t *testing.T) {
 }
 
 func TestLogWithProductionDefaults(t *testing.T) {
-	// Initialize logger with production defaults
-	logger, err := newLogger(WithProductionDefaults())
+	// Initialize logger with production defaults, writing to a temporary file
+	logger, err := newLogger(WithProductionDefaults(), WithFileOutput(filepath.Join(t.TempDir(), "app.log")))
 	if err != nil {
 		t.Fatalf("Failed to create logger with production defaults: %v", err)
 	}
@@ -0,0 +1,208 @@
+// Package loggertest provides loggers recording their entries in memory,
+// with helpers to assert on them in tests.
+package loggertest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/laziness-coders/go-utils/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// Logs holds the entries recorded by a logger created with New or
+// ReplaceGlobal. It is safe for concurrent use.
+type Logs struct {
+	observed *observer.ObservedLogs
+}
+
+// New creates a logger recording entries at debug level and above, without
+// writing them anywhere. opts are applied after these defaults, e.g.
+// logger.WithLevel to test level handling.
+// Usage:
+//
+//	log, logs := loggertest.New(t)
+//	svc := NewService(log)
+//	svc.Run(ctx)
+//	logs.AssertLogged(t, logger.LevelError, "payment failed", zap.String("order_id", "42"))
+func New(t testing.TB, opts ...logger.Option) (*logger.Logger, *Logs) {
+	t.Helper()
+	core, observed := observer.New(zapcore.DebugLevel)
+	opts = append([]logger.Option{
+		logger.WithLevel(logger.LevelDebug),
+		logger.WithSink(logger.Sink{Output: logger.OutputCore, Core: core}),
+	}, opts...)
+	l, err := logger.New(opts...)
+	if err != nil {
+		t.Fatalf("loggertest.New: %v", err)
+	}
+	return l, &Logs{observed: observed}
+}
+
+// ReplaceGlobal records the entries of the global logger for the duration of
+// the test, see logger.ReplaceForTest.
+func ReplaceGlobal(t testing.TB, opts ...logger.Option) *Logs {
+	t.Helper()
+	core, observed := observer.New(zapcore.DebugLevel)
+	opts = append(opts, logger.WithSink(logger.Sink{Output: logger.OutputCore, Core: core}))
+	logger.ReplaceForTest(t, opts...)
+	return &Logs{observed: observed}
+}
+
+// Entries returns the recorded entries, oldest first.
+func (l *Logs) Entries() []observer.LoggedEntry {
+	return l.observed.All()
+}
+
+// Len returns the number of recorded entries.
+func (l *Logs) Len() int {
+	return l.observed.Len()
+}
+
+// Messages returns the messages of the recorded entries.
+func (l *Logs) Messages() []string {
+	entries := l.Entries()
+	messages := make([]string, len(entries))
+	for i, e := range entries {
+		messages[i] = e.Message
+	}
+	return messages
+}
+
+// Reset discards the recorded entries.
+func (l *Logs) Reset() {
+	l.observed.TakeAll()
+}
+
+// FilterLevel returns the entries at the given level, e.g. logger.LevelError.
+// It panics on invalid levels.
+func (l *Logs) FilterLevel(level string) []observer.LoggedEntry {
+	lvl := mustParseLevel(level)
+	return l.observed.FilterLevelExact(lvl).All()
+}
+
+// FilterMessage returns the entries whose message contains substr.
+func (l *Logs) FilterMessage(substr string) []observer.LoggedEntry {
+	return l.observed.FilterMessageSnippet(substr).All()
+}
+
+// FilterField returns the entries with the given field.
+func (l *Logs) FilterField(field zap.Field) []observer.LoggedEntry {
+	var matched []observer.LoggedEntry
+	for _, e := range l.Entries() {
+		if hasFields(e, field) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// Find returns the entries at level with exactly msg, having at least the
+// given fields. Field values are compared as they would be encoded, so
+// zap.Int("n", 1) matches the sugared "n", 1.
+func (l *Logs) Find(level, msg string, fields ...zap.Field) []observer.LoggedEntry {
+	lvl := mustParseLevel(level)
+	var matched []observer.LoggedEntry
+	for _, e := range l.Entries() {
+		if e.Level == lvl && e.Message == msg && hasFields(e, fields...) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// AssertLogged fails the test unless an entry at level with exactly msg and
+// at least the given fields was recorded.
+func (l *Logs) AssertLogged(t testing.TB, level, msg string, fields ...zap.Field) bool {
+	t.Helper()
+	if len(l.Find(level, msg, fields...)) > 0 {
+		return true
+	}
+	t.Errorf("no %s entry %q with fields %v; recorded:\n%s", level, msg, fieldsMap(fields), l.dump())
+	return false
+}
+
+// AssertNotLogged fails the test if an entry at level with exactly msg and
+// at least the given fields was recorded.
+func (l *Logs) AssertNotLogged(t testing.TB, level, msg string, fields ...zap.Field) bool {
+	t.Helper()
+	if len(l.Find(level, msg, fields...)) == 0 {
+		return true
+	}
+	t.Errorf("unexpected %s entry %q with fields %v; recorded:\n%s", level, msg, fieldsMap(fields), l.dump())
+	return false
+}
+
+// hasFields reports whether the entry has all fields with equal values.
+func hasFields(e observer.LoggedEntry, fields ...zap.Field) bool {
+	if len(fields) == 0 {
+		return true
+	}
+	actual := e.ContextMap()
+	for key, want := range fieldsMap(fields) {
+		got, ok := actual[key]
+		if !ok || !reflect.DeepEqual(normalize(got), normalize(want)) {
+			return false
+		}
+	}
+	return true
+}
+
+func fieldsMap(fields []zap.Field) map[string]interface{} {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return enc.Fields
+}
+
+// normalize widens numbers, so that fields of different integer sizes compare equal.
+func normalize(v interface{}) interface{} {
+	switch n := v.(type) {
+	case int:
+		return int64(n)
+	case int8:
+		return int64(n)
+	case int16:
+		return int64(n)
+	case int32:
+		return int64(n)
+	case uint8:
+		return uint64(n)
+	case uint16:
+		return uint64(n)
+	case uint32:
+		return uint64(n)
+	case uint:
+		return uint64(n)
+	case float32:
+		return float64(n)
+	default:
+		return v
+	}
+}
+
+// dump formats the recorded entries for failure messages.
+func (l *Logs) dump() string {
+	entries := l.Entries()
+	if len(entries) == 0 {
+		return "  (none)"
+	}
+	var b strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&b, "  %s %q %v\n", e.Level.CapitalString(), e.Message, e.ContextMap())
+	}
+	return b.String()
+}
+
+func mustParseLevel(level string) zapcore.Level {
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		panic(fmt.Sprintf("loggertest: invalid log level: %s", level))
+	}
+	return lvl
+}
@@ -0,0 +1,131 @@
+package loggertest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/laziness-coders/go-utils/logger"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// recordingT records failures instead of failing the test.
+type recordingT struct {
+	testing.TB
+	failed bool
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(string, ...interface{}) { t.failed = true }
+
+func TestLogs_AssertLogged(t *testing.T) {
+	log, logs := New(t)
+	log.Info("user created", zap.String("user_id", "42"), zap.Int("age", 30))
+	log.Sugar().Warnw("slow query", "table", "users", "rows", 1200)
+	log.Error("payment failed", zap.Error(errors.New("declined")))
+
+	tests := []struct {
+		name   string
+		level  string
+		msg    string
+		fields []zap.Field
+		want   bool
+	}{
+		{"message only", logger.LevelInfo, "user created", nil, true},
+		{"field subset", logger.LevelInfo, "user created", []zap.Field{zap.String("user_id", "42")}, true},
+		{"int sizes compare equal", logger.LevelInfo, "user created", []zap.Field{zap.Int64("age", 30)}, true},
+		{"sugared fields", logger.LevelWarn, "slow query", []zap.Field{zap.String("table", "users"), zap.Int("rows", 1200)}, true},
+		{"error field", logger.LevelError, "payment failed", []zap.Field{zap.Error(errors.New("declined"))}, true},
+		{"wrong level", logger.LevelError, "user created", nil, false},
+		{"wrong value", logger.LevelInfo, "user created", []zap.Field{zap.String("user_id", "7")}, false},
+		{"missing field", logger.LevelInfo, "user created", []zap.Field{zap.String("email", "a@b.c")}, false},
+		{"partial message", logger.LevelInfo, "user", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &recordingT{TB: t}
+			got := logs.AssertLogged(rt, tt.level, tt.msg, tt.fields...)
+			if got != tt.want || rt.failed == tt.want {
+				t.Errorf("AssertLogged() = %v (failed: %v), want %v", got, rt.failed, tt.want)
+			}
+
+			rt = &recordingT{TB: t}
+			if got := logs.AssertNotLogged(rt, tt.level, tt.msg, tt.fields...); got == tt.want {
+				t.Errorf("AssertNotLogged() = %v, want %v", got, !tt.want)
+			}
+		})
+	}
+}
+
+func TestLogs_Filters(t *testing.T) {
+	log, logs := New(t)
+	log.Debug("cache miss", zap.String("key", "a"))
+	log.Info("cache hit", zap.String("key", "b"))
+	log.Sugar().Infof("cache size %d", 3)
+	log.Error("db down")
+
+	if got := logs.Len(); got != 4 {
+		t.Fatalf("Len() = %d, want 4", got)
+	}
+	if got := len(logs.FilterLevel(logger.LevelInfo)); got != 2 {
+		t.Errorf("FilterLevel(info) = %d entries, want 2", got)
+	}
+	if got := len(logs.FilterMessage("cache")); got != 3 {
+		t.Errorf("FilterMessage(cache) = %d entries, want 3", got)
+	}
+	if got := logs.FilterField(zap.String("key", "b")); len(got) != 1 || got[0].Message != "cache hit" {
+		t.Errorf("FilterField(key=b) = %v, want the cache hit entry", got)
+	}
+	want := []string{"cache miss", "cache hit", "cache size 3", "db down"}
+	for i, msg := range logs.Messages() {
+		if msg != want[i] {
+			t.Errorf("Messages()[%d] = %q, want %q", i, msg, want[i])
+		}
+	}
+
+	logs.Reset()
+	if got := logs.Len(); got != 0 {
+		t.Errorf("Len() after Reset = %d, want 0", got)
+	}
+}
+
+func TestNew_Options(t *testing.T) {
+	log, logs := New(t, logger.WithLevel(logger.LevelWarn))
+	log.Info("dropped")
+	log.Warn("kept")
+
+	if got := logs.Messages(); len(got) != 1 || got[0] != "kept" {
+		t.Errorf("Messages() = %v, want [kept]", got)
+	}
+}
+
+func TestLogs_Trace(t *testing.T) {
+	log, logs := New(t)
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02},
+		SpanID:     trace.SpanID{0x03},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanCtx)
+
+	log.Trace(ctx).Info("handled")
+	log.Sugar().Trace(ctx).Infow("handled sugared", "status", 200)
+
+	traceFields := []zap.Field{
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+	}
+	logs.AssertLogged(t, logger.LevelInfo, "handled", traceFields...)
+	logs.AssertLogged(t, logger.LevelInfo, "handled sugared", append(traceFields, zap.Int("status", 200))...)
+}
+
+func TestReplaceGlobal(t *testing.T) {
+	logs := ReplaceGlobal(t)
+	logger.Info("global entry", zap.Bool("ok", true))
+	logger.Sugar().Debugw("global sugared", "n", 1)
+
+	logs.AssertLogged(t, logger.LevelInfo, "global entry", zap.Bool("ok", true))
+	logs.AssertLogged(t, logger.LevelDebug, "global sugared", zap.Int("n", 1))
+}
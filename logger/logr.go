@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// logrSink writes logr entries through a Logger.
+type logrSink struct {
+	logger *Logger
+	fields []zap.Field // key/value pairs of WithValues
+	depth  int         // frames between the caller and the sink methods
+}
+
+// Logr returns a logr.Logger writing through l. V(0) entries are logged at
+// info level, more verbose ones at debug level, and errors at error level
+// with an "error" field. Entries get the caller of the logr call.
+// Trace fields are kept when l carries them, e.g. l.Ctx(ctx).Logr().
+// Usage:
+//
+//	otel.SetLogger(logger.GetLogger().Named("otel").Logr())
+func (l *Logger) Logr() logr.Logger {
+	return logr.New(&logrSink{logger: l})
+}
+
+// logrLevel maps logr verbosity to zap levels.
+func logrLevel(level int) zapcore.Level {
+	if level > 0 {
+		return zapcore.DebugLevel
+	}
+	return zapcore.InfoLevel
+}
+
+func (s *logrSink) Init(info logr.RuntimeInfo) {
+	s.depth += info.CallDepth
+}
+
+func (s *logrSink) Enabled(level int) bool {
+	return s.logger.enabled(logrLevel(level))
+}
+
+func (s *logrSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.write(logrLevel(level), msg, keysAndValues)
+}
+
+func (s *logrSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.write(zapcore.ErrorLevel, msg, keysAndValues, zap.Error(err))
+}
+
+// write logs an entry; it must be called directly by Info or Error for the
+// caller to be right.
+func (s *logrSink) write(lvl zapcore.Level, msg string, keysAndValues []interface{}, extra ...zap.Field) {
+	ent := zapcore.Entry{
+		Level:      lvl,
+		Time:       time.Now(),
+		LoggerName: s.logger.Name(),
+		Message:    msg,
+	}
+	// Skip write and Info/Error, then the logr frames.
+	if pc, file, line, ok := runtime.Caller(2 + s.depth); ok {
+		ent.Caller = zapcore.NewEntryCaller(pc, file, line, true)
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			ent.Caller.Function = fn.Name()
+		}
+	}
+	ce := s.logger.Core().Check(ent, nil)
+	if ce == nil {
+		return
+	}
+	fields := append(append([]zap.Field(nil), s.fields...), extra...)
+	ce.Write(appendKeysAndValues(fields, keysAndValues)...)
+}
+
+func (s *logrSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	fields := append([]zap.Field(nil), s.fields...)
+	return &logrSink{logger: s.logger, fields: appendKeysAndValues(fields, keysAndValues), depth: s.depth}
+}
+
+func (s *logrSink) WithName(name string) logr.LogSink {
+	return &logrSink{logger: s.logger.Named(name), fields: s.fields, depth: s.depth}
+}
+
+func (s *logrSink) WithCallDepth(depth int) logr.LogSink {
+	return &logrSink{logger: s.logger, fields: s.fields, depth: s.depth + depth}
+}
+
+// appendKeysAndValues converts alternating keys and values to fields, like
+// zap's sugared logger. zap.Field values are used as is; non-string keys
+// and a dangling key are reported under "!BADKEY".
+func appendKeysAndValues(fields []zap.Field, keysAndValues []interface{}) []zap.Field {
+	for i := 0; i < len(keysAndValues); {
+		if f, ok := keysAndValues[i].(zap.Field); ok {
+			fields = append(fields, f)
+			i++
+			continue
+		}
+		if i == len(keysAndValues)-1 {
+			fields = append(fields, zap.Any("!BADKEY", keysAndValues[i]))
+			break
+		}
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprintf("!BADKEY(%v)", keysAndValues[i])
+		}
+		fields = append(fields, zap.Any(key, keysAndValues[i+1]))
+		i += 2
+	}
+	return fields
+}
+
+var (
+	_ logr.LogSink          = (*logrSink)(nil)
+	_ logr.CallDepthLogSink = (*logrSink)(nil)
+)
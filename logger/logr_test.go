@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLogger_Logr(t *testing.T) {
+	logger, out := newJSONTestLogger(t, WithLevel(LevelInfo))
+	ctx := testSpanContext(t)
+	logr := logger.Ctx(ctx).Named("otel").Logr().WithValues("component", "exporter")
+
+	if logr.V(1).Enabled() {
+		t.Error("V(1) should be disabled at info level")
+	}
+	logr.V(1).Info("dropped")
+	caller := callerHere()
+	logr.Info("exported", "spans", 3)
+	logr.WithName("grpc").Error(errors.New("unavailable"), "export failed", "attempt", 2, "dangling")
+
+	entries := decodeLines(t, out)
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2: %v", len(entries), entries)
+	}
+
+	tests := []struct {
+		name string
+		want map[string]interface{}
+	}{
+		{"info", map[string]interface{}{
+			"level":     "INFO",
+			"logger":    "otel",
+			"msg":       "exported",
+			"caller":    caller,
+			"component": "exporter",
+			"spans":     float64(3),
+			"trace_id":  "4bf92f3577b34da6a3ce929d0e0e4736",
+		}},
+		{"error", map[string]interface{}{
+			"level":     "ERROR",
+			"logger":    "otel.grpc",
+			"msg":       "export failed",
+			"component": "exporter",
+			"error":     "unavailable",
+			"attempt":   float64(2),
+			"!BADKEY":   "dangling",
+			"trace_id":  "4bf92f3577b34da6a3ce929d0e0e4736",
+		}},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.want {
+				if entries[i][k] != v {
+					t.Errorf("%s = %v, want %v", k, entries[i][k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestLogrLevel(t *testing.T) {
+	logger, out := newJSONTestLogger(t)
+	logr := logger.Logr()
+	logr.V(0).Info("v0")
+	logr.V(1).Info("v1")
+	logr.V(4).Info("v4")
+
+	want := []string{"INFO", "DEBUG", "DEBUG"}
+	for i, entry := range decodeLines(t, out) {
+		if entry["level"] != want[i] {
+			t.Errorf("%s level = %v, want %s", entry["msg"], entry["level"], want[i])
+		}
+	}
+}
@@ -1,17 +1,60 @@
 package logger
 
-import "github.com/laziness-coders/go-utils/generic"
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/laziness-coders/go-utils/generic"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.uber.org/zap/zapcore"
+)
 
 // Option is a functional option for configuring the logger.
 type Option func(*Config)
 
-// WithLevel sets the log level.
+// WithConfig starts from cfg, e.g. decoded by configs.ConfigLoader.
+// Options after it override its fields.
+func WithConfig(cfg Config) Option {
+	return func(c *Config) {
+		*c = cfg
+		// Defaults are applied in place: keep the caller's slices and maps intact.
+		c.Sinks = slices.Clone(cfg.Sinks)
+		c.LevelOverrides = maps.Clone(cfg.LevelOverrides)
+	}
+}
+
+// WithLevel sets the log level. It also accepts a spec with per-logger
+// overrides such as "info,payments=debug" (see WithLevelOverride).
 func WithLevel(level string) Option {
 	return func(cfg *Config) {
 		cfg.Level = level
 	}
 }
 
+// WithLevelOverride sets the level of the Named logger with the given name.
+func WithLevelOverride(name, level string) Option {
+	return func(cfg *Config) {
+		if cfg.LevelOverrides == nil {
+			cfg.LevelOverrides = make(map[string]string)
+		}
+		cfg.LevelOverrides[name] = level
+	}
+}
+
+// WithLevelOverrides sets the levels of Named loggers, keyed by logger name.
+func WithLevelOverrides(overrides map[string]string) Option {
+	return func(cfg *Config) {
+		if cfg.LevelOverrides == nil {
+			cfg.LevelOverrides = make(map[string]string, len(overrides))
+		}
+		for name, level := range overrides {
+			cfg.LevelOverrides[name] = level
+		}
+	}
+}
+
 // WithFormat sets the log format.
 func WithFormat(format string) Option {
 	return func(cfg *Config) {
@@ -54,13 +97,163 @@ func WithCompress(compress bool) Option {
 	}
 }
 
-// WithDev sets whether to use zap's development config for human-readable output.
+// WithRotation rotates file outputs by time and size, with file names from
+// a pattern and separate files per level.
+// Usage:
+//
+//	logger.WithRotation(logger.Rotation{
+//		Interval: 24 * time.Hour,
+//		Pattern:  "{service}-{date}.log",
+//		Service:  "svc",
+//		Levels:   map[string]string{logger.LevelError: "error.log"},
+//	})
+func WithRotation(rotation Rotation) Option {
+	return func(cfg *Config) {
+		cfg.Rotation = &rotation
+	}
+}
+
+// WithDev sets whether to enable zap's development mode, in which DPanic
+// panics. It does not change the format: use WithFormat(FormatPretty).
 func WithDev(isDev bool) Option {
 	return func(cfg *Config) {
 		cfg.IsDev = generic.ToPointer(isDev)
 	}
 }
 
+// WithSink adds an output with its own format, level and writer.
+// Once a sink other than otel is added, Format and FilePath no longer select the output.
+func WithSink(sink Sink) Option {
+	return func(cfg *Config) {
+		cfg.Sinks = append(cfg.Sinks, sink)
+	}
+}
+
+// WithSinks adds several outputs, see WithSink.
+func WithSinks(sinks ...Sink) Option {
+	return func(cfg *Config) {
+		cfg.Sinks = append(cfg.Sinks, sinks...)
+	}
+}
+
+// WithOTelSink exports entries as OpenTelemetry log records to provider,
+// in addition to the other outputs. A nil provider uses the global one.
+func WithOTelSink(provider otellog.LoggerProvider) Option {
+	return WithSink(Sink{Output: OutputOTel, LoggerProvider: provider})
+}
+
+// WithRedaction enables masking of sensitive data with the given rules.
+func WithRedaction(rules Redaction) Option {
+	return func(cfg *Config) {
+		cfg.Redaction = &rules
+	}
+}
+
+// WithDefaultRedaction enables masking of passwords, tokens, secrets,
+// authorization headers, JWTs and card numbers.
+func WithDefaultRedaction() Option {
+	return WithRedaction(DefaultRedaction())
+}
+
+// WithRedactKeys masks the values of fields whose key contains one of keys.
+// It extends the current rules, starting from DefaultRedaction if none are set.
+func WithRedactKeys(keys ...string) Option {
+	return func(cfg *Config) {
+		if cfg.Redaction == nil {
+			cfg.Redaction = generic.ToPointer(DefaultRedaction())
+		}
+		cfg.Redaction.Keys = append(cfg.Redaction.Keys, keys...)
+	}
+}
+
+// WithRedactPatterns masks the parts of string values matching one of patterns.
+// It extends the current rules, starting from DefaultRedaction if none are set.
+func WithRedactPatterns(patterns ...*regexp.Regexp) Option {
+	return func(cfg *Config) {
+		if cfg.Redaction == nil {
+			cfg.Redaction = generic.ToPointer(DefaultRedaction())
+		}
+		cfg.Redaction.Patterns = append(cfg.Redaction.Patterns, patterns...)
+	}
+}
+
+// WithSampling logs the first initial entries with the same level and message
+// per tick, then every thereafter-th one.
+func WithSampling(tick time.Duration, initial, thereafter int) Option {
+	return func(cfg *Config) {
+		cfg.Sampling = &Sampling{Tick: tick, Initial: initial, Thereafter: thereafter}
+	}
+}
+
+// WithRateLimit logs at most burst entries per message within each window and
+// reports the dropped ones with a summary line when the window closes.
+func WithRateLimit(window time.Duration, burst int) Option {
+	return func(cfg *Config) {
+		cfg.RateLimit = &RateLimit{Window: window, Burst: burst}
+	}
+}
+
+// WithBuffering buffers up to size bytes per output and flushes at least
+// every flushInterval. Zero values use the defaults (256 kB, 30s).
+func WithBuffering(size int, flushInterval time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.Buffering = &Buffering{Size: size, FlushInterval: flushInterval}
+	}
+}
+
+// WithClock sets the clock used for timestamps and rate limit windows.
+// Mostly useful in tests.
+func WithClock(clock zapcore.Clock) Option {
+	return func(cfg *Config) {
+		cfg.Clock = clock
+	}
+}
+
+// WithTraceCorrelation sets the trace fields logged by Trace and Ctx.
+func WithTraceCorrelation(tc TraceCorrelation) Option {
+	return func(cfg *Config) {
+		cfg.TraceCorrelation = tc
+	}
+}
+
+// WithTraceFormats selects the trace field formats (TraceFormat* constants).
+func WithTraceFormats(formats ...string) Option {
+	return func(cfg *Config) {
+		cfg.TraceCorrelation.Formats = formats
+	}
+}
+
+// WithDatadogCorrelation adds the Datadog trace format with its unified service tags.
+func WithDatadogCorrelation(service, env, version string) Option {
+	return func(cfg *Config) {
+		cfg.TraceCorrelation.Formats = appendTraceFormat(cfg.TraceCorrelation.Formats, TraceFormatDatadog)
+		cfg.TraceCorrelation.Service = service
+		cfg.TraceCorrelation.Env = env
+		cfg.TraceCorrelation.Version = version
+	}
+}
+
+// WithGCPCorrelation adds the Google Cloud Logging trace format.
+func WithGCPCorrelation(projectID string) Option {
+	return func(cfg *Config) {
+		cfg.TraceCorrelation.Formats = appendTraceFormat(cfg.TraceCorrelation.Formats, TraceFormatGCP)
+		cfg.TraceCorrelation.GCPProjectID = projectID
+	}
+}
+
+// appendTraceFormat adds format once. An empty list means otel, which is kept.
+func appendTraceFormat(formats []string, format string) []string {
+	if len(formats) == 0 {
+		formats = []string{TraceFormatOTel}
+	}
+	for _, f := range formats {
+		if f == format {
+			return formats
+		}
+	}
+	return append(formats, format)
+}
+
 // Predefined option sets for common configurations
 
 // WithConsoleOutput configures console output (JSON format).
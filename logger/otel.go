@@ -0,0 +1,453 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// otelScope is the instrumentation scope of the records emitted by OTel sinks.
+const otelScope = "github.com/laziness-coders/go-utils/logger"
+
+// spanContextKey names the field carrying the span context of Trace and Ctx
+// to OTel sinks. Its type is SkipType, so encoders do not write it.
+const spanContextKey = "otel.span_context"
+
+// spanContextField returns the hidden field with the span context of ctx.
+func spanContextField(ctx context.Context) (zap.Field, bool) {
+	if ctx == nil {
+		return zap.Field{}, false
+	}
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsValid() {
+		return zap.Field{}, false
+	}
+	return zap.Field{Key: spanContextKey, Type: zapcore.SkipType, Interface: spanCtx}, true
+}
+
+// otelSeverity maps zap levels to OpenTelemetry severities.
+func otelSeverity(level zapcore.Level) otellog.Severity {
+	switch level {
+	case zapcore.DebugLevel:
+		return otellog.SeverityDebug
+	case zapcore.InfoLevel:
+		return otellog.SeverityInfo
+	case zapcore.WarnLevel:
+		return otellog.SeverityWarn
+	case zapcore.ErrorLevel:
+		return otellog.SeverityError
+	case zapcore.DPanicLevel:
+		return otellog.SeverityFatal1
+	case zapcore.PanicLevel:
+		return otellog.SeverityFatal2
+	case zapcore.FatalLevel:
+		return otellog.SeverityFatal3
+	default:
+		return otellog.SeverityUndefined
+	}
+}
+
+// otelCore converts entries to OpenTelemetry log records: fields become
+// attributes and the span context of Trace and Ctx becomes the record's
+// trace context.
+type otelCore struct {
+	zapcore.LevelEnabler
+	provider otellog.LoggerProvider
+	logger   otellog.Logger
+	redactor *redactor // nil without redaction
+
+	fields []zapcore.Field // context fields, encoded on Write to honor namespaces
+}
+
+func newOTelCore(provider otellog.LoggerProvider, enab zapcore.LevelEnabler, r *redactor) zapcore.Core {
+	if provider == nil {
+		provider = global.GetLoggerProvider()
+	}
+	return &otelCore{
+		LevelEnabler: enab,
+		provider:     provider,
+		logger:       provider.Logger(otelScope),
+		redactor:     r,
+	}
+}
+
+func (c *otelCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(slices.Clip(c.fields), fields...)
+	return &clone
+}
+
+func (c *otelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *otelCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := &otelObjectEncoder{}
+	var objEnc zapcore.ObjectEncoder = enc
+	msg := ent.Message
+	if c.redactor != nil {
+		objEnc = &redactObjectEncoder{ObjectEncoder: enc, r: c.redactor}
+		msg = c.redactor.redactString(msg)
+	}
+
+	var spanCtx trace.SpanContext
+	for _, group := range [][]zapcore.Field{c.fields, fields} {
+		for _, f := range group {
+			if f.Type == zapcore.SkipType && f.Key == spanContextKey {
+				spanCtx, _ = f.Interface.(trace.SpanContext)
+				continue
+			}
+			f.AddTo(objEnc)
+		}
+	}
+
+	var record otellog.Record
+	record.SetTimestamp(ent.Time)
+	record.SetSeverity(otelSeverity(ent.Level))
+	record.SetSeverityText(ent.Level.CapitalString())
+	record.SetBody(otellog.StringValue(msg))
+	record.AddAttributes(enc.result()...)
+	if ent.LoggerName != "" {
+		record.AddAttributes(otellog.String("logger.name", ent.LoggerName))
+	}
+	if ent.Caller.Defined {
+		record.AddAttributes(
+			otellog.String("code.file.path", ent.Caller.File),
+			otellog.Int("code.line.number", ent.Caller.Line),
+		)
+		if ent.Caller.Function != "" {
+			record.AddAttributes(otellog.String("code.function.name", ent.Caller.Function))
+		}
+	}
+	if ent.Stack != "" {
+		record.AddAttributes(otellog.String("code.stacktrace", ent.Stack))
+	}
+
+	ctx := context.Background()
+	if spanCtx.IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, spanCtx)
+	}
+	c.logger.Emit(ctx, record)
+	return nil
+}
+
+// Sync flushes the provider when it supports it, as the SDK provider does.
+func (c *otelCore) Sync() error {
+	if f, ok := c.provider.(interface{ ForceFlush(context.Context) error }); ok {
+		return f.ForceFlush(context.Background())
+	}
+	return nil
+}
+
+// otelNamespace collects the attributes added after OpenNamespace.
+type otelNamespace struct {
+	key string
+	kvs []otellog.KeyValue
+}
+
+// otelObjectEncoder converts zap fields to OpenTelemetry attributes.
+type otelObjectEncoder struct {
+	kvs []otellog.KeyValue
+	ns  []otelNamespace // open namespaces, innermost last
+}
+
+func (e *otelObjectEncoder) add(kv otellog.KeyValue) {
+	if n := len(e.ns); n > 0 {
+		e.ns[n-1].kvs = append(e.ns[n-1].kvs, kv)
+		return
+	}
+	e.kvs = append(e.kvs, kv)
+}
+
+// result closes the open namespaces and returns the attributes.
+func (e *otelObjectEncoder) result() []otellog.KeyValue {
+	for i := len(e.ns) - 1; i >= 0; i-- {
+		kv := otellog.Map(e.ns[i].key, e.ns[i].kvs...)
+		if i > 0 {
+			e.ns[i-1].kvs = append(e.ns[i-1].kvs, kv)
+		} else {
+			e.kvs = append(e.kvs, kv)
+		}
+	}
+	e.ns = nil
+	return e.kvs
+}
+
+func (e *otelObjectEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	values := &otelArrayEncoder{}
+	err := arr.MarshalLogArray(values)
+	e.add(otellog.Slice(key, values.values...))
+	return err
+}
+
+func (e *otelObjectEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	sub := &otelObjectEncoder{}
+	err := obj.MarshalLogObject(sub)
+	e.add(otellog.Map(key, sub.result()...))
+	return err
+}
+
+func (e *otelObjectEncoder) AddBinary(key string, value []byte) {
+	e.add(otellog.Bytes(key, value))
+}
+
+func (e *otelObjectEncoder) AddByteString(key string, value []byte) {
+	e.add(otellog.String(key, string(value)))
+}
+
+func (e *otelObjectEncoder) AddBool(key string, value bool) {
+	e.add(otellog.Bool(key, value))
+}
+
+func (e *otelObjectEncoder) AddComplex128(key string, value complex128) {
+	e.add(otellog.String(key, fmt.Sprint(value)))
+}
+
+func (e *otelObjectEncoder) AddComplex64(key string, value complex64) {
+	e.AddComplex128(key, complex128(value))
+}
+
+func (e *otelObjectEncoder) AddDuration(key string, value time.Duration) {
+	e.add(otellog.Int64(key, value.Nanoseconds()))
+}
+
+func (e *otelObjectEncoder) AddFloat64(key string, value float64) {
+	e.add(otellog.Float64(key, value))
+}
+
+func (e *otelObjectEncoder) AddFloat32(key string, value float32) {
+	e.AddFloat64(key, float64(value))
+}
+
+func (e *otelObjectEncoder) AddInt(key string, value int) {
+	e.add(otellog.Int(key, value))
+}
+
+func (e *otelObjectEncoder) AddInt64(key string, value int64) {
+	e.add(otellog.Int64(key, value))
+}
+
+func (e *otelObjectEncoder) AddInt32(key string, value int32) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *otelObjectEncoder) AddInt16(key string, value int16) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *otelObjectEncoder) AddInt8(key string, value int8) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *otelObjectEncoder) AddString(key, value string) {
+	e.add(otellog.String(key, value))
+}
+
+func (e *otelObjectEncoder) AddTime(key string, value time.Time) {
+	e.add(otellog.String(key, value.Format(time.RFC3339Nano)))
+}
+
+func (e *otelObjectEncoder) AddUint(key string, value uint) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *otelObjectEncoder) AddUint64(key string, value uint64) {
+	e.add(otellog.KeyValue{Key: key, Value: otelUint64(value)})
+}
+
+func (e *otelObjectEncoder) AddUint32(key string, value uint32) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *otelObjectEncoder) AddUint16(key string, value uint16) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *otelObjectEncoder) AddUint8(key string, value uint8) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *otelObjectEncoder) AddUintptr(key string, value uintptr) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *otelObjectEncoder) AddReflected(key string, value interface{}) error {
+	e.add(otellog.KeyValue{Key: key, Value: otelReflected(value)})
+	return nil
+}
+
+func (e *otelObjectEncoder) OpenNamespace(key string) {
+	e.ns = append(e.ns, otelNamespace{key: key})
+}
+
+// otelArrayEncoder converts zap array elements to OpenTelemetry values.
+type otelArrayEncoder struct {
+	values []otellog.Value
+}
+
+func (e *otelArrayEncoder) AppendArray(arr zapcore.ArrayMarshaler) error {
+	values := &otelArrayEncoder{}
+	err := arr.MarshalLogArray(values)
+	e.values = append(e.values, otellog.SliceValue(values.values...))
+	return err
+}
+
+func (e *otelArrayEncoder) AppendObject(obj zapcore.ObjectMarshaler) error {
+	sub := &otelObjectEncoder{}
+	err := obj.MarshalLogObject(sub)
+	e.values = append(e.values, otellog.MapValue(sub.result()...))
+	return err
+}
+
+func (e *otelArrayEncoder) AppendReflected(value interface{}) error {
+	e.values = append(e.values, otelReflected(value))
+	return nil
+}
+
+func (e *otelArrayEncoder) AppendBool(value bool) {
+	e.values = append(e.values, otellog.BoolValue(value))
+}
+
+func (e *otelArrayEncoder) AppendByteString(value []byte) {
+	e.values = append(e.values, otellog.StringValue(string(value)))
+}
+
+func (e *otelArrayEncoder) AppendComplex128(value complex128) {
+	e.values = append(e.values, otellog.StringValue(fmt.Sprint(value)))
+}
+
+func (e *otelArrayEncoder) AppendComplex64(value complex64) {
+	e.AppendComplex128(complex128(value))
+}
+
+func (e *otelArrayEncoder) AppendDuration(value time.Duration) {
+	e.values = append(e.values, otellog.Int64Value(value.Nanoseconds()))
+}
+
+func (e *otelArrayEncoder) AppendFloat64(value float64) {
+	e.values = append(e.values, otellog.Float64Value(value))
+}
+
+func (e *otelArrayEncoder) AppendFloat32(value float32) {
+	e.AppendFloat64(float64(value))
+}
+
+func (e *otelArrayEncoder) AppendInt(value int) {
+	e.AppendInt64(int64(value))
+}
+
+func (e *otelArrayEncoder) AppendInt64(value int64) {
+	e.values = append(e.values, otellog.Int64Value(value))
+}
+
+func (e *otelArrayEncoder) AppendInt32(value int32) {
+	e.AppendInt64(int64(value))
+}
+
+func (e *otelArrayEncoder) AppendInt16(value int16) {
+	e.AppendInt64(int64(value))
+}
+
+func (e *otelArrayEncoder) AppendInt8(value int8) {
+	e.AppendInt64(int64(value))
+}
+
+func (e *otelArrayEncoder) AppendString(value string) {
+	e.values = append(e.values, otellog.StringValue(value))
+}
+
+func (e *otelArrayEncoder) AppendTime(value time.Time) {
+	e.values = append(e.values, otellog.StringValue(value.Format(time.RFC3339Nano)))
+}
+
+func (e *otelArrayEncoder) AppendUint(value uint) {
+	e.AppendUint64(uint64(value))
+}
+
+func (e *otelArrayEncoder) AppendUint64(value uint64) {
+	e.values = append(e.values, otelUint64(value))
+}
+
+func (e *otelArrayEncoder) AppendUint32(value uint32) {
+	e.AppendInt64(int64(value))
+}
+
+func (e *otelArrayEncoder) AppendUint16(value uint16) {
+	e.AppendInt64(int64(value))
+}
+
+func (e *otelArrayEncoder) AppendUint8(value uint8) {
+	e.AppendInt64(int64(value))
+}
+
+func (e *otelArrayEncoder) AppendUintptr(value uintptr) {
+	e.AppendUint64(uint64(value))
+}
+
+// otelUint64 keeps values over MaxInt64 exact by falling back to a string.
+func otelUint64(value uint64) otellog.Value {
+	if value > math.MaxInt64 {
+		return otellog.StringValue(fmt.Sprint(value))
+	}
+	return otellog.Int64Value(int64(value))
+}
+
+// otelReflected converts an arbitrary value through its JSON form, keeping
+// integers exact. Values that cannot be marshaled are formatted with %+v.
+func otelReflected(value interface{}) otellog.Value {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return otellog.StringValue(fmt.Sprintf("%+v", value))
+	}
+	decoded, err := decodeJSON(data)
+	if err != nil {
+		return otellog.StringValue(string(data))
+	}
+	return otelJSONValue(decoded)
+}
+
+func otelJSONValue(v interface{}) otellog.Value {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		kvs := make([]otellog.KeyValue, 0, len(val))
+		for _, k := range slices.Sorted(maps.Keys(val)) {
+			kvs = append(kvs, otellog.KeyValue{Key: k, Value: otelJSONValue(val[k])})
+		}
+		return otellog.MapValue(kvs...)
+	case []interface{}:
+		values := make([]otellog.Value, 0, len(val))
+		for _, item := range val {
+			values = append(values, otelJSONValue(item))
+		}
+		return otellog.SliceValue(values...)
+	case string:
+		return otellog.StringValue(val)
+	case bool:
+		return otellog.BoolValue(val)
+	case json.Number:
+		if n, err := val.Int64(); err == nil {
+			return otellog.Int64Value(n)
+		}
+		if n, err := strconv.ParseUint(val.String(), 10, 64); err == nil {
+			return otelUint64(n)
+		}
+		f, _ := val.Float64()
+		return otellog.Float64Value(f)
+	default:
+		return otellog.Value{}
+	}
+}
@@ -0,0 +1,274 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// memoryLogExporter keeps the exported records in memory.
+type memoryLogExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *memoryLogExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *memoryLogExporter) Shutdown(context.Context) error   { return nil }
+func (e *memoryLogExporter) ForceFlush(context.Context) error { return nil }
+
+func (e *memoryLogExporter) Records() []sdklog.Record {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]sdklog.Record(nil), e.records...)
+}
+
+func newOTelTestLogger(t *testing.T, opts ...Option) (*Logger, *memoryLogExporter) {
+	t.Helper()
+	exporter := &memoryLogExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	logger, _ := newJSONTestLogger(t, append([]Option{WithOTelSink(provider)}, opts...)...)
+	return logger, exporter
+}
+
+func recordAttrs(r sdklog.Record) map[string]otellog.Value {
+	attrs := make(map[string]otellog.Value)
+	r.WalkAttributes(func(kv otellog.KeyValue) bool {
+		attrs[kv.Key] = kv.Value
+		return true
+	})
+	return attrs
+}
+
+type testUser struct {
+	Name string
+	Age  int
+}
+
+func (u testUser) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", u.Name)
+	enc.AddInt("age", u.Age)
+	return nil
+}
+
+func TestOTelSink_Record(t *testing.T) {
+	logger, exporter := newOTelTestLogger(t)
+
+	logger.Named("orders").With(zap.String("service", "api")).Warn("order failed",
+		zap.Int("attempt", 3),
+		zap.Bool("retry", true),
+		zap.Error(errors.New("db down")),
+		zap.Object("user", testUser{Name: "alice", Age: 30}),
+		zap.Strings("tags", []string{"a", "b"}),
+		zap.Any("meta", map[string]interface{}{"region": "eu", "shard": 2}),
+	)
+
+	records := exporter.Records()
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	r := records[0]
+	if r.Severity() != otellog.SeverityWarn || r.SeverityText() != "WARN" {
+		t.Errorf("severity = %v %q, want WARN", r.Severity(), r.SeverityText())
+	}
+	if r.Body().AsString() != "order failed" {
+		t.Errorf("body = %q", r.Body().AsString())
+	}
+	if r.Timestamp().IsZero() {
+		t.Error("timestamp should be set")
+	}
+	if r.TraceID().IsValid() {
+		t.Error("record without Trace(ctx) should have no trace context")
+	}
+
+	attrs := recordAttrs(r)
+	for key, want := range map[string]string{
+		"service":     "api",
+		"error":       "db down",
+		"logger.name": "orders",
+	} {
+		if got := attrs[key].AsString(); got != want {
+			t.Errorf("attribute %s = %q, want %q", key, got, want)
+		}
+	}
+	if attrs["attempt"].AsInt64() != 3 || !attrs["retry"].AsBool() {
+		t.Errorf("unexpected attempt/retry attributes: %v %v", attrs["attempt"], attrs["retry"])
+	}
+	if !strings.HasSuffix(attrs["code.file.path"].AsString(), ".go") || attrs["code.line.number"].AsInt64() == 0 {
+		t.Errorf("caller attributes missing: %v %v", attrs["code.file.path"], attrs["code.line.number"])
+	}
+
+	user := attrs["user"].AsMap()
+	if len(user) != 2 || user[0].Key != "name" || user[0].Value.AsString() != "alice" || user[1].Value.AsInt64() != 30 {
+		t.Errorf("user attribute = %v", attrs["user"])
+	}
+	if tags := attrs["tags"].AsSlice(); len(tags) != 2 || tags[1].AsString() != "b" {
+		t.Errorf("tags attribute = %v", attrs["tags"])
+	}
+	meta := attrs["meta"].AsMap()
+	if len(meta) != 2 || meta[0].Key != "region" || meta[1].Value.AsInt64() != 2 {
+		t.Errorf("meta attribute = %v", attrs["meta"])
+	}
+}
+
+func TestOTelSink_ReflectedNumbers(t *testing.T) {
+	logger, exporter := newOTelTestLogger(t)
+
+	logger.Info("batch", zap.Any("batch", map[string]interface{}{
+		"id":    int64(1) << 60,
+		"max":   uint64(math.MaxUint64),
+		"ratio": 0.25,
+	}))
+
+	records := exporter.Records()
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	batch := recordAttrs(records[0])["batch"].AsMap()
+	if len(batch) != 3 {
+		t.Fatalf("batch attribute = %v", batch)
+	}
+	if id := batch[0].Value; id.Kind() != otellog.KindInt64 || id.AsInt64() != 1<<60 {
+		t.Errorf("id = %v, want Int64 %d", id, int64(1)<<60)
+	}
+	if largest := batch[1].Value; largest.AsString() != "18446744073709551615" {
+		t.Errorf("max = %v, want the exact value as a string", largest)
+	}
+	if ratio := batch[2].Value; ratio.Kind() != otellog.KindFloat64 || ratio.AsFloat64() != 0.25 {
+		t.Errorf("ratio = %v, want Float64 0.25", ratio)
+	}
+}
+
+func TestOTelSink_Severity(t *testing.T) {
+	tests := []struct {
+		level zapcore.Level
+		want  otellog.Severity
+	}{
+		{zapcore.DebugLevel, otellog.SeverityDebug},
+		{zapcore.InfoLevel, otellog.SeverityInfo},
+		{zapcore.WarnLevel, otellog.SeverityWarn},
+		{zapcore.ErrorLevel, otellog.SeverityError},
+		{zapcore.DPanicLevel, otellog.SeverityFatal1},
+		{zapcore.PanicLevel, otellog.SeverityFatal2},
+		{zapcore.FatalLevel, otellog.SeverityFatal3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			if got := otelSeverity(tt.level); got != tt.want {
+				t.Errorf("otelSeverity(%s) = %v, want %v", tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOTelSink_TraceContext(t *testing.T) {
+	logger, exporter := newOTelTestLogger(t, WithTraceFormats(TraceFormatDatadog))
+	ctx := testSpanContext(t)
+
+	logger.Trace(ctx).Info("traced")
+	logger.Ctx(ctx).Sugar().Infow("sugared", "k", "v")
+
+	records := exporter.Records()
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	for _, r := range records {
+		if r.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || r.SpanID().String() != "00f067aa0ba902b7" {
+			t.Errorf("%s: trace context = %s/%s", r.Body().AsString(), r.TraceID(), r.SpanID())
+		}
+		if !r.TraceFlags().IsSampled() {
+			t.Errorf("%s: trace flags should be sampled", r.Body().AsString())
+		}
+		if _, ok := recordAttrs(r)[spanContextKey]; ok {
+			t.Errorf("%s: the span context field should not become an attribute", r.Body().AsString())
+		}
+	}
+}
+
+func TestOTelSink_NextToOutput(t *testing.T) {
+	exporter := &memoryLogExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	out := &syncBuffer{}
+	logger, err := newLogger(
+		WithLevel(LevelInfo),
+		WithDev(false),
+		WithSink(Sink{Format: FormatJSON, Output: OutputWriter, Writer: out}),
+		WithSink(Sink{Output: OutputOTel, LoggerProvider: provider, Level: LevelWarn}),
+	)
+	if err != nil {
+		t.Fatalf("newLogger() error = %v", err)
+	}
+
+	ctx := testSpanContext(t)
+	logger.Trace(ctx).Info("info only in the writer")
+	logger.Trace(ctx).Warn("warn in both")
+
+	if n := strings.Count(out.String(), "\n"); n != 2 {
+		t.Errorf("writer got %d lines, want 2", n)
+	}
+	if strings.Contains(out.String(), spanContextKey) {
+		t.Errorf("the span context field should not be encoded: %s", out.String())
+	}
+	if records := exporter.Records(); len(records) != 1 || records[0].Body().AsString() != "warn in both" {
+		t.Errorf("unexpected records: %v", records)
+	}
+}
+
+func TestOTelSink_KeepsLegacyOutput(t *testing.T) {
+	cfg, err := newConfig(WithFormat(FormatJSON), WithOTelSink(nil))
+	if err != nil {
+		t.Fatalf("newConfig() error = %v", err)
+	}
+	if _, isOTel := cfg.buildZapCore(&outputs{}).(*otelCore); isOTel {
+		t.Error("an otel sink alone should not replace the stdout output")
+	}
+}
+
+func TestOTelSink_Redaction(t *testing.T) {
+	logger, exporter := newOTelTestLogger(t, WithDefaultRedaction())
+
+	logger.Info("token eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.sig",
+		zap.String("password", "hunter2"),
+		zap.Object("user", testUser{Name: "alice"}),
+	)
+
+	r := exporter.Records()[0]
+	if strings.Contains(r.Body().AsString(), "eyJ") {
+		t.Errorf("body should be redacted: %q", r.Body().AsString())
+	}
+	if got := recordAttrs(r)["password"].AsString(); got != DefaultRedactMask {
+		t.Errorf("password = %q, want %q", got, DefaultRedactMask)
+	}
+}
+
+func TestOTelSink_Namespace(t *testing.T) {
+	logger, exporter := newOTelTestLogger(t)
+
+	logger.With(zap.Namespace("http"), zap.String("method", "GET")).Info("request", zap.Int("status", 200))
+
+	attrs := recordAttrs(exporter.Records()[0])
+	http := attrs["http"].AsMap()
+	if len(http) != 2 || http[0].Key != "method" || http[1].Key != "status" {
+		t.Errorf("http attribute = %v", attrs["http"])
+	}
+}
@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"os"
+	"strings"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// ANSI escape sequences of the pretty encoder.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiRed     = "\x1b[31m"
+	ansiYellow  = "\x1b[33m"
+	ansiBlue    = "\x1b[34m"
+	ansiMagenta = "\x1b[35m"
+	ansiCyan    = "\x1b[36m"
+)
+
+const (
+	prettyTimeLayout  = "15:04:05.000"
+	prettyBlockIndent = "    "
+)
+
+// prettyEncoder writes entries for humans, in color on terminals:
+//
+//	12:00:00.000 ERROR payments charge.go:42 charge failed order_id=42
+//	    errorVerbose:
+//	        card declined
+//	        main.charge
+//	            /app/charge.go:40
+//
+// Fields whose value spans several lines, and stack traces, are printed as
+// indented blocks below the entry.
+type prettyEncoder struct {
+	*fieldCollector
+	color bool
+}
+
+// newPrettyEncoder creates a pretty encoder, writing ANSI colors if color is set.
+func newPrettyEncoder(color bool) zapcore.Encoder {
+	cfg := zapcore.EncoderConfig{
+		EncodeTime:     zapcore.TimeEncoderOfLayout(prettyTimeLayout),
+		EncodeDuration: zapcore.StringDurationEncoder,
+	}
+	return &prettyEncoder{
+		fieldCollector: &fieldCollector{cfg: &cfg},
+		color:          color,
+	}
+}
+
+// colorEnabled reports whether colors can be written to f: it is a terminal
+// and the NO_COLOR environment variable (https://no-color.org) is not set.
+func colorEnabled(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (e *prettyEncoder) Clone() zapcore.Encoder {
+	return &prettyEncoder{fieldCollector: e.fieldCollector.clone(), color: e.color}
+}
+
+// paint wraps s in the ANSI color, if colors are enabled.
+func (e *prettyEncoder) paint(buf *buffer.Buffer, color, s string) {
+	if !e.color {
+		buf.AppendString(s)
+		return
+	}
+	buf.AppendString(color)
+	buf.AppendString(s)
+	buf.AppendString(ansiReset)
+}
+
+func prettyLevelColor(level zapcore.Level) string {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return ansiMagenta
+	case level == zapcore.InfoLevel:
+		return ansiBlue
+	case level == zapcore.WarnLevel:
+		return ansiYellow
+	default:
+		return ansiRed
+	}
+}
+
+func (e *prettyEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := e.fieldCollector.clone()
+	for i := range fields {
+		fields[i].AddTo(final)
+	}
+
+	buf := encoderPool.Get()
+	e.paint(buf, ansiDim, ent.Time.Format(prettyTimeLayout))
+	buf.AppendByte(' ')
+	level := ent.Level.CapitalString()
+	e.paint(buf, prettyLevelColor(ent.Level), level)
+	buf.AppendString(strings.Repeat(" ", max(0, 5-len(level))))
+	if ent.LoggerName != "" {
+		buf.AppendByte(' ')
+		e.paint(buf, ansiBold, ent.LoggerName)
+	}
+	if ent.Caller.Defined {
+		buf.AppendByte(' ')
+		e.paint(buf, ansiDim, ent.Caller.TrimmedPath())
+	}
+	buf.AppendByte(' ')
+	buf.AppendString(ent.Message)
+
+	var blocks []encodedField
+	for _, f := range final.fields {
+		if strings.Contains(f.value, "\n") {
+			blocks = append(blocks, f)
+			continue
+		}
+		buf.AppendByte(' ')
+		e.paint(buf, ansiCyan, logfmtKey(f.key))
+		buf.AppendByte('=')
+		buf.AppendString(logfmtValue(f.value))
+	}
+	buf.AppendByte('\n')
+
+	if ent.Stack != "" {
+		blocks = append(blocks, encodedField{key: "stacktrace", value: ent.Stack})
+	}
+	for _, block := range blocks {
+		buf.AppendString(prettyBlockIndent)
+		e.paint(buf, ansiCyan, block.key)
+		buf.AppendString(":\n")
+		for _, line := range strings.Split(strings.TrimRight(block.value, "\n"), "\n") {
+			buf.AppendString(prettyBlockIndent + prettyBlockIndent)
+			buf.AppendString(line)
+			buf.AppendByte('\n')
+		}
+	}
+	return buf, nil
+}
@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestPrettyEncoder(t *testing.T) {
+	failed := testEntry(zapcore.ErrorLevel, "charge failed")
+	failed.Stack = "main.charge\n\t/app/charge.go:40\n"
+
+	tests := []struct {
+		name   string
+		color  bool
+		entry  zapcore.Entry
+		fields []zapcore.Field
+		want   string
+	}{
+		{
+			name:   "plain",
+			entry:  testEntry(zapcore.InfoLevel, "charge created"),
+			fields: []zapcore.Field{zap.Int("order_id", 42), zap.String("card", "visa debit"), zap.Duration("took", time.Second)},
+			want:   "12:00:00.000 INFO  payments logger/charge.go:42 charge created order_id=42 card=\"visa debit\" took=1s\n",
+		},
+		{
+			name:   "color",
+			color:  true,
+			entry:  testEntry(zapcore.WarnLevel, "slow"),
+			fields: []zapcore.Field{zap.Int("order_id", 42)},
+			want: "\x1b[2m12:00:00.000\x1b[0m \x1b[33mWARN\x1b[0m  \x1b[1mpayments\x1b[0m \x1b[2mlogger/charge.go:42\x1b[0m slow " +
+				"\x1b[36morder_id\x1b[0m=42\n",
+		},
+		{
+			name:   "multi-line fields and stacktrace",
+			entry:  failed,
+			fields: []zapcore.Field{zap.Error(stackError{msg: "card declined"}), zap.String("query", "SELECT *\nFROM orders")},
+			want: "12:00:00.000 ERROR payments logger/charge.go:42 charge failed error=\"card declined\"\n" +
+				"    errorVerbose:\n" +
+				"        card declined\n" +
+				"        main.charge\n" +
+				"        \t/app/charge.go:40\n" +
+				"    query:\n" +
+				"        SELECT *\n" +
+				"        FROM orders\n" +
+				"    stacktrace:\n" +
+				"        main.charge\n" +
+				"        \t/app/charge.go:40\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := newPrettyEncoder(tt.color).(*prettyEncoder)
+			buf, err := enc.EncodeEntry(tt.entry, tt.fields)
+			if err != nil {
+				t.Fatalf("EncodeEntry() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("EncodeEntry() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "app.log"))
+	if err != nil {
+		t.Fatalf("os.Create() error = %v", err)
+	}
+	defer file.Close()
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err == nil {
+		defer tty.Close()
+	}
+
+	t.Setenv("NO_COLOR", "")
+	if colorEnabled(file) {
+		t.Error("colors should be disabled for files")
+	}
+	if tty != nil && !colorEnabled(tty) {
+		t.Error("colors should be enabled for terminals")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if tty != nil && colorEnabled(tty) {
+		t.Error("NO_COLOR should disable colors")
+	}
+}
+
+func TestLogger_PrettySinkWithoutColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	out := &syncBuffer{}
+	logger, err := newLogger(WithSinks(
+		Sink{Format: FormatPretty, Output: OutputWriter, Writer: out},
+		Sink{Format: FormatPretty, Output: OutputFile, FilePath: logFile},
+	))
+	if err != nil {
+		t.Fatalf("newLogger() error = %v", err)
+	}
+	logger.Info("charge created")
+	_ = logger.Sync()
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	for name, got := range map[string]string{"writer": out.String(), "file": string(data)} {
+		if !strings.Contains(got, "charge created") || strings.Contains(got, "\x1b[") {
+			t.Errorf("%s sink = %q, want the entry without colors", name, got)
+		}
+	}
+}
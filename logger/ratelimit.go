@@ -0,0 +1,201 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Sampling configures zap's sampler: within each Tick, the first Initial
+// entries with the same level and message are logged, then every
+// Thereafter-th one.
+type Sampling struct {
+	Tick       time.Duration `mapstructure:"TICK"`       // sampling interval (default: 1s)
+	Initial    int           `mapstructure:"INITIAL"`    // entries logged per tick before sampling (default: 100)
+	Thereafter int           `mapstructure:"THEREAFTER"` // then log every Nth entry (default: 100)
+}
+
+// RateLimit caps the entries per message within each Window. Entries over
+// Burst are dropped and reported with a summary line "suppressed N similar
+// messages", written by the first entry after the window closes, or on Sync.
+type RateLimit struct {
+	Window time.Duration `mapstructure:"WINDOW"` // rate limit window (default: 1s)
+	Burst  int           `mapstructure:"BURST"`  // entries per message and window (default: 10)
+}
+
+const (
+	defaultSamplingTick       = time.Second
+	defaultSamplingInitial    = 100
+	defaultSamplingThereafter = 100
+	defaultRateLimitWindow    = time.Second
+	defaultRateLimitBurst     = 10
+)
+
+func (s Sampling) setDefaults() Sampling {
+	if s.Tick <= 0 {
+		s.Tick = defaultSamplingTick
+	}
+	if s.Initial <= 0 {
+		s.Initial = defaultSamplingInitial
+	}
+	if s.Thereafter <= 0 {
+		s.Thereafter = defaultSamplingThereafter
+	}
+	return s
+}
+
+func (r RateLimit) setDefaults() RateLimit {
+	if r.Window <= 0 {
+		r.Window = defaultRateLimitWindow
+	}
+	if r.Burst <= 0 {
+		r.Burst = defaultRateLimitBurst
+	}
+	return r
+}
+
+// rateLimitKey identifies "similar" messages.
+type rateLimitKey struct {
+	level zapcore.Level
+	name  string
+	msg   string
+}
+
+// rateLimiter counts entries per key within clock-aligned windows.
+// It is shared by a core and all of its With clones.
+type rateLimiter struct {
+	window time.Duration
+	burst  int
+	clock  zapcore.Clock
+	out    zapcore.Core // writes summaries, without context fields
+
+	mu     sync.Mutex
+	epoch  int64 // index of the current window
+	counts map[rateLimitKey]int
+}
+
+// rateLimitCore drops entries over the limit of their rateLimiter.
+type rateLimitCore struct {
+	zapcore.Core
+	limiter *rateLimiter
+}
+
+func newRateLimitCore(core zapcore.Core, cfg RateLimit, clock zapcore.Clock) zapcore.Core {
+	return &rateLimitCore{
+		Core: core,
+		limiter: &rateLimiter{
+			window: cfg.Window,
+			burst:  cfg.Burst,
+			clock:  clock,
+			out:    core,
+			counts: make(map[rateLimitKey]int),
+		},
+	}
+}
+
+func (c *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rateLimitCore{Core: c.Core.With(fields), limiter: c.limiter}
+}
+
+func (c *rateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	if !c.limiter.allow(ent) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// Sync reports the pending suppressed counts before flushing.
+func (c *rateLimitCore) Sync() error {
+	c.limiter.flush()
+	return c.Core.Sync()
+}
+
+// allow counts the entry and reports whether it is within the limit.
+// Summaries of a closed window are written first.
+func (l *rateLimiter) allow(ent zapcore.Entry) bool {
+	now := l.clock.Now()
+	epoch := now.UnixNano() / int64(l.window)
+
+	l.mu.Lock()
+	var summaries []zapcore.Entry
+	var suppressed []int
+	if epoch != l.epoch {
+		summaries, suppressed = l.drainLocked(now)
+		l.epoch = epoch
+	}
+
+	key := rateLimitKey{level: ent.Level, name: ent.LoggerName, msg: ent.Message}
+	l.counts[key]++
+	allowed := l.counts[key] <= l.burst
+	l.mu.Unlock()
+
+	l.writeSummaries(summaries, suppressed)
+	return allowed
+}
+
+// flush writes the summaries of the current window and starts a new one.
+func (l *rateLimiter) flush() {
+	l.mu.Lock()
+	summaries, suppressed := l.drainLocked(l.clock.Now())
+	l.mu.Unlock()
+
+	l.writeSummaries(summaries, suppressed)
+}
+
+// drainLocked resets the counters and returns a summary entry for every key
+// that went over the limit. l.mu must be held.
+func (l *rateLimiter) drainLocked(now time.Time) ([]zapcore.Entry, []int) {
+	var (
+		keys       []rateLimitKey
+		summaries  []zapcore.Entry
+		suppressed []int
+	)
+	for key, n := range l.counts {
+		if n > l.burst {
+			keys = append(keys, key)
+		}
+	}
+	// Sort for a stable output order.
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		if keys[i].msg != keys[j].msg {
+			return keys[i].msg < keys[j].msg
+		}
+		return keys[i].level < keys[j].level
+	})
+
+	for _, key := range keys {
+		summaries = append(summaries, zapcore.Entry{
+			Level:      key.level,
+			Time:       now,
+			LoggerName: key.name,
+			Message:    key.msg,
+		})
+		suppressed = append(suppressed, l.counts[key]-l.burst)
+	}
+	clear(l.counts)
+	return summaries, suppressed
+}
+
+func (l *rateLimiter) writeSummaries(summaries []zapcore.Entry, suppressed []int) {
+	for i, ent := range summaries {
+		msg := ent.Message
+		ent.Message = fmt.Sprintf("suppressed %d similar messages", suppressed[i])
+		if ce := l.out.Check(ent, nil); ce != nil {
+			ce.Write(
+				zap.String("suppressed_msg", msg),
+				zap.Int("suppressed_count", suppressed[i]),
+				zap.Duration("window", l.window),
+			)
+		}
+	}
+}
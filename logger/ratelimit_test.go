@@ -0,0 +1,138 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testClock is a manually advanced zapcore.Clock.
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) NewTicker(d time.Duration) *time.Ticker {
+	return time.NewTicker(d)
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestLogger_RateLimit(t *testing.T) {
+	clock := newTestClock()
+	logger, out := newJSONTestLogger(t,
+		WithClock(clock),
+		WithRateLimit(time.Second, 2),
+	)
+
+	for i := 0; i < 5; i++ {
+		logger.Error("disk full")
+	}
+	logger.Named("db").Error("disk full")
+	logger.Info("other message")
+
+	if got := strings.Count(out.String(), `"msg":"disk full"`); got != 3 {
+		t.Fatalf("got %d entries, want 3:\n%s", got, out.String())
+	}
+	if strings.Contains(out.String(), "suppressed") {
+		t.Fatalf("summary written before the window closed:\n%s", out.String())
+	}
+
+	// The next entry after the window closes reports the suppressed ones.
+	clock.Add(time.Second)
+	logger.Error("disk full")
+
+	entries := decodeLines(t, out)
+	if len(entries) != 6 {
+		t.Fatalf("got %d lines, want 6:\n%s", len(entries), out.String())
+	}
+	entry := entries[4]
+	if entry["msg"] != "suppressed 3 similar messages" {
+		t.Errorf("msg = %v, want summary", entry["msg"])
+	}
+	if entry["suppressed_msg"] != "disk full" || entry["suppressed_count"] != float64(3) || entry["level"] != "ERROR" {
+		t.Errorf("unexpected summary: %v", entry)
+	}
+	if entry["ts"] != "2026-10-17T12:00:01.000Z" {
+		t.Errorf("summary ts = %v, want clock time", entry["ts"])
+	}
+	if got := strings.Count(out.String(), "suppressed "); got != 1 {
+		t.Errorf("got %d summaries, want 1:\n%s", got, out.String())
+	}
+}
+
+func TestLogger_RateLimitSync(t *testing.T) {
+	clock := newTestClock()
+	logger, out := newJSONTestLogger(t,
+		WithClock(clock),
+		WithRateLimit(time.Minute, 1),
+	)
+
+	child := logger.Trace(context.Background()).Named("worker")
+	for i := 0; i < 3; i++ {
+		child.Warn("retrying")
+	}
+	_ = logger.Sync()
+
+	entry := decodeLastLine(t, out)
+	if entry["msg"] != "suppressed 2 similar messages" || entry["logger"] != "worker" {
+		t.Errorf("unexpected summary after Sync: %v", entry)
+	}
+
+	// Counters restart after a flush.
+	child.Warn("retrying")
+	if got := strings.Count(out.String(), `"msg":"retrying"`); got != 2 {
+		t.Errorf("got %d entries, want 2:\n%s", got, out.String())
+	}
+}
+
+func TestLogger_Sampling(t *testing.T) {
+	clock := newTestClock()
+	logger, out := newJSONTestLogger(t,
+		WithClock(clock),
+		WithSampling(time.Second, 2, 3),
+	)
+
+	for i := 0; i < 10; i++ {
+		logger.Info("hot loop")
+	}
+	// 1st, 2nd, then every 3rd: 5th and 8th.
+	if got := strings.Count(out.String(), "hot loop"); got != 4 {
+		t.Errorf("got %d entries in the first tick, want 4", got)
+	}
+
+	clock.Add(time.Second)
+	logger.Info("hot loop")
+	if got := strings.Count(out.String(), "hot loop"); got != 5 {
+		t.Errorf("got %d entries after the tick, want 5", got)
+	}
+}
+
+func TestConfig_SamplingDefaults(t *testing.T) {
+	cfg, err := newConfig(WithSampling(0, 0, 0), WithRateLimit(0, 0))
+	if err != nil {
+		t.Fatalf("newConfig() error = %v", err)
+	}
+	if *cfg.Sampling != (Sampling{Tick: time.Second, Initial: 100, Thereafter: 100}) {
+		t.Errorf("unexpected sampling defaults: %+v", *cfg.Sampling)
+	}
+	if *cfg.RateLimit != (RateLimit{Window: time.Second, Burst: 10}) {
+		t.Errorf("unexpected rate limit defaults: %+v", *cfg.RateLimit)
+	}
+}
@@ -0,0 +1,350 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultRedactMask replaces redacted data unless Redaction.Mask is set.
+const DefaultRedactMask = "[REDACTED]"
+
+var (
+	// DefaultRedactKeys are the field keys masked by default, matched
+	// case-insensitively as substrings (e.g. "db_password", "X-Auth-Token").
+	DefaultRedactKeys = []string{"password", "token", "secret", "authorization"}
+
+	// JWTPattern matches JSON Web Tokens.
+	JWTPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+
+	// CardNumberPattern matches payment card numbers of 13 to 19 digits,
+	// optionally grouped with spaces or dashes. Redaction only masks the
+	// matches passing the Luhn checksum, not e.g. timestamps or IDs.
+	CardNumberPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+)
+
+// Redaction configures masking of sensitive data before it is written.
+type Redaction struct {
+	// Keys are case-insensitive substrings of field keys whose values are masked.
+	// They apply to string, binary, object, array and reflected values, at any depth.
+	Keys []string
+	// Patterns mask the matching parts of string values and messages.
+	Patterns []*regexp.Regexp
+	// Mask replaces redacted data (default: "[REDACTED]").
+	Mask string
+}
+
+// DefaultRedaction returns rules masking passwords, tokens, secrets,
+// authorization headers, JWTs and card numbers.
+func DefaultRedaction() Redaction {
+	return Redaction{
+		Keys:     append([]string(nil), DefaultRedactKeys...),
+		Patterns: []*regexp.Regexp{JWTPattern, CardNumberPattern},
+		Mask:     DefaultRedactMask,
+	}
+}
+
+// IsSensitiveKey reports whether values of key are masked.
+func (r Redaction) IsSensitiveKey(key string) bool {
+	return newRedactor(r).isSensitiveKey(key)
+}
+
+// RedactString masks the parts of s matching the patterns.
+func (r Redaction) RedactString(s string) string {
+	return newRedactor(r).redactString(s)
+}
+
+// RedactJSON masks the values of sensitive keys, at any depth, and the
+// pattern matches in a JSON document. Invalid JSON, e.g. a truncated body,
+// is handled as a string.
+func (r Redaction) RedactJSON(data []byte) []byte {
+	red := newRedactor(r)
+	decoded, err := decodeJSON(data)
+	if err != nil {
+		return []byte(red.redactString(string(data)))
+	}
+	redacted, err := json.Marshal(red.redactValue(decoded))
+	if err != nil {
+		return []byte(red.redactString(string(data)))
+	}
+	return redacted
+}
+
+// redactor applies Redaction rules to keys and values.
+type redactor struct {
+	keys     []string
+	patterns []*regexp.Regexp
+	mask     string
+}
+
+func newRedactor(r Redaction) *redactor {
+	mask := r.Mask
+	if mask == "" {
+		mask = DefaultRedactMask
+	}
+	return &redactor{keys: r.Keys, patterns: r.Patterns, mask: mask}
+}
+
+func (r *redactor) isSensitiveKey(key string) bool {
+	for _, k := range r.keys {
+		if containsFold(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *redactor) redactString(s string) string {
+	for _, p := range r.patterns {
+		if !p.MatchString(s) {
+			continue
+		}
+		if p == CardNumberPattern {
+			s = p.ReplaceAllStringFunc(s, func(match string) string {
+				if luhnValid(match) {
+					return r.mask
+				}
+				return match
+			})
+			continue
+		}
+		s = p.ReplaceAllLiteralString(s, r.mask)
+	}
+	return s
+}
+
+// luhnValid reports whether the digits of s pass the Luhn checksum of card
+// numbers. Other characters are ignored.
+func luhnValid(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// redactValue walks a decoded JSON value, masking sensitive keys and values.
+func (r *redactor) redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			if r.isSensitiveKey(k) {
+				val[k] = r.mask
+				continue
+			}
+			val[k] = r.redactValue(item)
+		}
+		return val
+	case []interface{}:
+		for i, item := range val {
+			val[i] = r.redactValue(item)
+		}
+		return val
+	case string:
+		return r.redactString(val)
+	default:
+		return val
+	}
+}
+
+// redactReflected converts an arbitrary value into maps and slices so it can be walked.
+// Values that cannot be converted are passed through unchanged for zap to report.
+func (r *redactor) redactReflected(obj interface{}) interface{} {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return obj
+	}
+	decoded, err := decodeJSON(data)
+	if err != nil {
+		return obj
+	}
+	return r.redactValue(decoded)
+}
+
+// decodeJSON decodes data into maps and slices, keeping numbers as
+// json.Number so that integers above 2^53 are not rounded.
+func decodeJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var decoded interface{}
+	if err := dec.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid JSON: data after the top-level value")
+	}
+	return decoded, nil
+}
+
+// containsFold reports whether substr is within s, ignoring ASCII case, without allocating.
+func containsFold(s, substr string) bool {
+	n := len(substr)
+	for i := 0; i+n <= len(s); i++ {
+		match := true
+		for j := 0; j < n; j++ {
+			if lowerASCII(s[i+j]) != lowerASCII(substr[j]) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+func lowerASCII(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
+
+// redactEncoder wraps an encoder, redacting context fields, entry fields and messages.
+type redactEncoder struct {
+	*redactObjectEncoder
+	enc zapcore.Encoder
+}
+
+func newRedactEncoder(enc zapcore.Encoder, r *redactor) zapcore.Encoder {
+	return &redactEncoder{
+		redactObjectEncoder: &redactObjectEncoder{ObjectEncoder: enc, r: r},
+		enc:                 enc,
+	}
+}
+
+func (e *redactEncoder) Clone() zapcore.Encoder {
+	return newRedactEncoder(e.enc.Clone(), e.r)
+}
+
+func (e *redactEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	ent.Message = e.r.redactString(ent.Message)
+	if len(fields) == 0 {
+		return e.enc.EncodeEntry(ent, nil)
+	}
+
+	// The wrapped encoder would add fields to its own clone, bypassing redaction.
+	final := e.Clone().(*redactEncoder)
+	for i := range fields {
+		fields[i].AddTo(final)
+	}
+	return final.enc.EncodeEntry(ent, nil)
+}
+
+// redactObjectEncoder redacts the values added to an object.
+type redactObjectEncoder struct {
+	zapcore.ObjectEncoder
+	r *redactor
+}
+
+func (e *redactObjectEncoder) AddString(key, value string) {
+	if e.r.isSensitiveKey(key) {
+		e.ObjectEncoder.AddString(key, e.r.mask)
+		return
+	}
+	e.ObjectEncoder.AddString(key, e.r.redactString(value))
+}
+
+func (e *redactObjectEncoder) AddByteString(key string, value []byte) {
+	if e.r.isSensitiveKey(key) {
+		e.ObjectEncoder.AddString(key, e.r.mask)
+		return
+	}
+	e.ObjectEncoder.AddString(key, e.r.redactString(string(value)))
+}
+
+func (e *redactObjectEncoder) AddBinary(key string, value []byte) {
+	if e.r.isSensitiveKey(key) {
+		e.ObjectEncoder.AddString(key, e.r.mask)
+		return
+	}
+	e.ObjectEncoder.AddBinary(key, value)
+}
+
+func (e *redactObjectEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	if e.r.isSensitiveKey(key) {
+		e.ObjectEncoder.AddString(key, e.r.mask)
+		return nil
+	}
+	return e.ObjectEncoder.AddObject(key, redactObject{obj: obj, r: e.r})
+}
+
+func (e *redactObjectEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	if e.r.isSensitiveKey(key) {
+		e.ObjectEncoder.AddString(key, e.r.mask)
+		return nil
+	}
+	return e.ObjectEncoder.AddArray(key, redactArray{arr: arr, r: e.r})
+}
+
+func (e *redactObjectEncoder) AddReflected(key string, obj interface{}) error {
+	if e.r.isSensitiveKey(key) {
+		e.ObjectEncoder.AddString(key, e.r.mask)
+		return nil
+	}
+	return e.ObjectEncoder.AddReflected(key, e.r.redactReflected(obj))
+}
+
+// redactArrayEncoder redacts the values appended to an array.
+type redactArrayEncoder struct {
+	zapcore.ArrayEncoder
+	r *redactor
+}
+
+func (e *redactArrayEncoder) AppendString(value string) {
+	e.ArrayEncoder.AppendString(e.r.redactString(value))
+}
+
+func (e *redactArrayEncoder) AppendByteString(value []byte) {
+	e.ArrayEncoder.AppendString(e.r.redactString(string(value)))
+}
+
+func (e *redactArrayEncoder) AppendObject(obj zapcore.ObjectMarshaler) error {
+	return e.ArrayEncoder.AppendObject(redactObject{obj: obj, r: e.r})
+}
+
+func (e *redactArrayEncoder) AppendArray(arr zapcore.ArrayMarshaler) error {
+	return e.ArrayEncoder.AppendArray(redactArray{arr: arr, r: e.r})
+}
+
+func (e *redactArrayEncoder) AppendReflected(obj interface{}) error {
+	return e.ArrayEncoder.AppendReflected(e.r.redactReflected(obj))
+}
+
+// redactObject makes an ObjectMarshaler write through a redacting encoder.
+type redactObject struct {
+	obj zapcore.ObjectMarshaler
+	r   *redactor
+}
+
+func (o redactObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return o.obj.MarshalLogObject(&redactObjectEncoder{ObjectEncoder: enc, r: o.r})
+}
+
+// redactArray makes an ArrayMarshaler write through a redacting encoder.
+type redactArray struct {
+	arr zapcore.ArrayMarshaler
+	r   *redactor
+}
+
+func (a redactArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return a.arr.MarshalLogArray(&redactArrayEncoder{ArrayEncoder: enc, r: a.r})
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"io"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func newBenchmarkLogger(b *testing.B, opts ...Option) *Logger {
+	b.Helper()
+	opts = append([]Option{
+		WithLevel(LevelInfo),
+		WithDev(false),
+		WithSink(Sink{Format: FormatJSON, Output: OutputWriter, Writer: io.Discard}),
+	}, opts...)
+	logger, err := newLogger(opts...)
+	if err != nil {
+		b.Fatalf("newLogger() error = %v", err)
+	}
+	return logger
+}
+
+func benchmarkRedactionFields() []zap.Field {
+	return []zap.Field{
+		zap.String("user", "alice"),
+		zap.String("path", "/api/v1/orders"),
+		zap.Int("status", 200),
+		zap.String("password", "hunter2"),
+		zap.Strings("tags", []string{"a", "b"}),
+	}
+}
+
+func BenchmarkRedaction_Disabled(b *testing.B) {
+	logger := newBenchmarkLogger(b)
+	fields := benchmarkRedactionFields()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Info("request handled", fields...)
+	}
+}
+
+func BenchmarkRedaction_Enabled(b *testing.B) {
+	logger := newBenchmarkLogger(b, WithDefaultRedaction())
+	fields := benchmarkRedactionFields()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Info("request handled", fields...)
+	}
+}
+
+func BenchmarkRedaction_EnabledReflected(b *testing.B) {
+	logger := newBenchmarkLogger(b, WithDefaultRedaction())
+	cfg := redactTestConfig{Host: "db.local", Password: "hunter2", Headers: map[string]string{"Accept": "json"}}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Info("config", zap.Any("cfg", cfg))
+	}
+}
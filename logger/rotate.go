@@ -0,0 +1,468 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Rotation configures file outputs to start new files by time and size,
+// named after a pattern, and to copy the entries of some levels to separate
+// files. The MaxAge, MaxBackups and Compress settings of the sink apply to
+// the old files of every pattern.
+//
+// The active file is named by expanding the pattern for the current
+// interval, e.g. "{service}-{date}.log" gives svc-2026-10-17.log. When the
+// active file must be rotated without its name changing, e.g. on size or for
+// a pattern without date, it is renamed with a timestamp first
+// (svc-2026-10-17-2026-10-17T14-03-10.000.log).
+type Rotation struct {
+	// Interval starts a new file every interval, e.g. 24h for daily files.
+	// Whole days start at midnight, counted from the Unix epoch for several
+	// days, shorter intervals at multiples of the interval (hourly files on
+	// the hour). Zero rotates by size only.
+	Interval time.Duration `mapstructure:"INTERVAL"`
+	// MaxSize is the size in MB at which the active file is rotated
+	// (default: the sink's MaxSize).
+	MaxSize int `mapstructure:"MAX_SIZE"`
+	// Pattern names the files within the directory of the sink's FilePath,
+	// with the placeholders {service}, {date} (2006-01-02) and {time} (15-04)
+	// of the interval start (default: "{service}-{date}.log" with an
+	// Interval, else "{service}.log").
+	Pattern string `mapstructure:"PATTERN"`
+	// Service replaces {service} (default: the name of the sink's FilePath
+	// without extension, or "app" if FilePath is a directory).
+	Service string `mapstructure:"SERVICE"`
+	// Levels also writes the entries at or above a level to another file,
+	// keyed by level, with its own pattern, e.g. {"error": "error.log"}.
+	// Patterns may use {level} too.
+	Levels map[string]string `mapstructure:"LEVELS"`
+	// LocalTime uses the local time for names and day boundaries (default: UTC).
+	LocalTime bool `mapstructure:"LOCAL_TIME"`
+}
+
+const (
+	defaultRotationService = "app"
+	rotationDateFormat     = "2006-01-02"
+	rotationTimeFormat     = "15-04"
+	rotationBackupFormat   = "2006-01-02T15-04-05.000"
+	day                    = 24 * time.Hour
+	bytesPerMB             = 1024 * 1024
+	logFilePermission      = 0o644
+	compressSuffix         = ".gz"
+)
+
+var rotationPlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+// rotationPath splits a sink's FilePath into the directory of the files and
+// the default service name. Paths without extension are directories.
+func rotationPath(filePath string) (dir, service string) {
+	if stat, err := os.Stat(filePath); (err == nil && stat.IsDir()) || filepath.Ext(filePath) == "" {
+		return filePath, defaultRotationService
+	}
+	base := filepath.Base(filePath)
+	return filepath.Dir(filePath), strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// setDefaults fills the zero values of the rotation for a sink.
+func (r Rotation) setDefaults(sink Sink) Rotation {
+	if r.MaxSize <= 0 {
+		r.MaxSize = sink.MaxSize
+	}
+	if r.Service == "" {
+		_, r.Service = rotationPath(sink.FilePath)
+	}
+	if r.Pattern == "" {
+		r.Pattern = "{service}.log"
+		if r.Interval > 0 {
+			r.Pattern = "{service}-{date}.log"
+		}
+	}
+	return r
+}
+
+// validate checks if the rotation is valid, reporting all invalid fields at once.
+func (r Rotation) validate() error {
+	var errs []error
+	if r.Interval < 0 {
+		errs = append(errs, fmt.Errorf("invalid rotation interval: %v", r.Interval))
+	}
+	if err := validateRotationPattern(r.Pattern, false); err != nil {
+		errs = append(errs, err)
+	}
+	levels := make([]string, 0, len(r.Levels))
+	for level := range r.Levels {
+		levels = append(levels, level)
+	}
+	sort.Strings(levels)
+	for _, level := range levels {
+		if _, err := parseLevel(level); err != nil {
+			errs = append(errs, fmt.Errorf("rotation levels: %w", err))
+			continue
+		}
+		if err := validateRotationPattern(r.Levels[level], true); err != nil {
+			errs = append(errs, fmt.Errorf("rotation level %s: %w", level, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateRotationPattern(pattern string, level bool) error {
+	if pattern == "" || filepath.Base(pattern) != pattern {
+		return fmt.Errorf("invalid rotation pattern: %q", pattern)
+	}
+	for _, placeholder := range rotationPlaceholder.FindAllString(pattern, -1) {
+		switch placeholder {
+		case "{service}", "{date}", "{time}":
+			// valid
+		case "{level}":
+			if !level {
+				return fmt.Errorf("rotation pattern %q: {level} is only valid in level patterns", pattern)
+			}
+		default:
+			return fmt.Errorf("rotation pattern %q: unknown placeholder %s", pattern, placeholder)
+		}
+	}
+	return nil
+}
+
+// rotationRoute is a level file of the Levels setting.
+type rotationRoute struct {
+	level   zapcore.Level
+	pattern string
+}
+
+// routes returns the level files ordered by level. Levels are checked by validate.
+func (r Rotation) routes() []rotationRoute {
+	routes := make([]rotationRoute, 0, len(r.Levels))
+	for level, pattern := range r.Levels {
+		lvl, _ := parseLevel(level)
+		routes = append(routes, rotationRoute{level: lvl, pattern: pattern})
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].level < routes[j].level })
+	return routes
+}
+
+// periodStart returns the start of the interval containing t.
+func (r Rotation) periodStart(t time.Time) time.Time {
+	if r.Interval%day != 0 {
+		return t.Truncate(r.Interval)
+	}
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if days := int(r.Interval / day); days > 1 {
+		// Align to days since the epoch, so restarts keep the same periods.
+		epochDays := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / int64(day/time.Second)
+		start = start.AddDate(0, 0, -int(epochDays%int64(days)))
+	}
+	return start
+}
+
+// periodEnd returns the end of the interval starting at start.
+func (r Rotation) periodEnd(start time.Time) time.Time {
+	if r.Interval%day != 0 {
+		return start.Add(r.Interval)
+	}
+	return start.AddDate(0, 0, int(r.Interval/day))
+}
+
+// rotatingFile is a file writer rotating by time and size.
+type rotatingFile struct {
+	rotation   Rotation
+	dir        string
+	pattern    string // with {service} and {level} expanded
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+	clock      zapcore.Clock
+
+	mu        sync.Mutex
+	file      *os.File
+	name      string
+	size      int64
+	periodEnd time.Time // zero without Interval
+	closed    bool      // set by Close: no more rotations, nor cleanups
+
+	cleanup   sync.WaitGroup
+	cleanupMu sync.Mutex // serializes the cleanups of old files
+}
+
+// newRotatingFile creates the writer of the sink's main file, or of a level
+// file if pattern is not empty.
+func newRotatingFile(sink Sink, pattern string, level zapcore.Level, clock zapcore.Clock) *rotatingFile {
+	r := *sink.Rotation
+	dir, _ := rotationPath(sink.FilePath)
+	if pattern == "" {
+		pattern = r.Pattern
+	}
+	pattern = strings.NewReplacer("{service}", r.Service, "{level}", level.String()).Replace(pattern)
+	return &rotatingFile{
+		rotation:   r,
+		dir:        dir,
+		pattern:    pattern,
+		maxSize:    int64(r.MaxSize) * bytesPerMB,
+		maxAge:     time.Duration(sink.MaxAge) * day,
+		maxBackups: sink.MaxBackups,
+		compress:   sink.compress(),
+		clock:      clock,
+	}
+}
+
+// now returns the clock time in the rotation's location.
+func (f *rotatingFile) now() time.Time {
+	if f.rotation.LocalTime {
+		return f.clock.Now().Local()
+	}
+	return f.clock.Now().UTC()
+}
+
+// fileName expands the pattern for the interval starting at start.
+func (f *rotatingFile) fileName(start time.Time) string {
+	return strings.NewReplacer(
+		"{date}", start.Format(rotationDateFormat),
+		"{time}", start.Format(rotationTimeFormat),
+	).Replace(f.pattern)
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	switch {
+	case f.file == nil:
+		if err := f.openLocked(now); err != nil {
+			return 0, err
+		}
+	case f.closed:
+		// Late entries of child loggers go to the file reopened after Close.
+	case !f.periodEnd.IsZero() && !now.Before(f.periodEnd),
+		f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize:
+		if err := f.rotateLocked(now); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// openLocked opens, or appends to, the file of the interval containing now.
+func (f *rotatingFile) openLocked(now time.Time) error {
+	start := now
+	f.periodEnd = time.Time{}
+	if f.rotation.Interval > 0 {
+		start = f.rotation.periodStart(now)
+		f.periodEnd = f.rotation.periodEnd(start)
+	}
+
+	if err := os.MkdirAll(f.dir, logDirPermission); err != nil {
+		return fmt.Errorf("failed to create log directory %s: %w", f.dir, err)
+	}
+	name := filepath.Join(f.dir, f.fileName(start))
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, logFilePermission)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	f.file, f.name, f.size = file, name, stat.Size()
+	return nil
+}
+
+// rotateLocked closes the active file, renames it if the next file would
+// have the same name, and opens the next one.
+func (f *rotatingFile) rotateLocked(now time.Time) error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	f.file = nil
+
+	next := now
+	if f.rotation.Interval > 0 {
+		next = f.rotation.periodStart(now)
+	}
+	if filepath.Join(f.dir, f.fileName(next)) == f.name {
+		if err := os.Rename(f.name, backupName(f.name, now)); err != nil {
+			return fmt.Errorf("failed to rename log file: %w", err)
+		}
+	}
+	if err := f.openLocked(now); err != nil {
+		return err
+	}
+
+	f.cleanup.Add(1)
+	go func() {
+		defer f.cleanup.Done()
+		f.removeOldFiles(now)
+	}()
+	return nil
+}
+
+// backupName inserts the rotation time before the extension of name.
+func backupName(name string, t time.Time) string {
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "-" + t.Format(rotationBackupFormat) + ext
+}
+
+// removeOldFiles compresses the old files of the pattern, then removes the
+// ones over MaxBackups or older than MaxAge.
+func (f *rotatingFile) removeOldFiles(now time.Time) {
+	f.cleanupMu.Lock()
+	defer f.cleanupMu.Unlock()
+
+	f.mu.Lock()
+	active := f.name
+	f.mu.Unlock()
+
+	old := f.oldFiles(active)
+	if f.compress {
+		for i, name := range old {
+			if !strings.HasSuffix(name, compressSuffix) && compressFile(name) == nil {
+				old[i] = name + compressSuffix
+			}
+		}
+	}
+
+	type oldFile struct {
+		name    string
+		modTime time.Time
+	}
+	files := make([]oldFile, 0, len(old))
+	for _, name := range old {
+		if stat, err := os.Stat(name); err == nil {
+			files = append(files, oldFile{name: name, modTime: stat.ModTime()})
+		}
+	}
+	// Newest first.
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].modTime.Equal(files[j].modTime) {
+			return files[i].modTime.After(files[j].modTime)
+		}
+		return files[i].name > files[j].name
+	})
+
+	for i, file := range files {
+		if (f.maxBackups > 0 && i >= f.maxBackups) || (f.maxAge > 0 && now.Sub(file.modTime) > f.maxAge) {
+			_ = os.Remove(file.name)
+		}
+	}
+}
+
+// oldFiles returns the files of the pattern and their backups, except active.
+func (f *rotatingFile) oldFiles(active string) []string {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil
+	}
+	match := f.oldFilePattern()
+	var names []string
+	for _, entry := range entries {
+		name := filepath.Join(f.dir, entry.Name())
+		if entry.Type().IsRegular() && name != active && match.MatchString(entry.Name()) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// oldFilePattern matches the names of the pattern, with an optional backup
+// timestamp and compression suffix.
+func (f *rotatingFile) oldFilePattern() *regexp.Regexp {
+	ext := filepath.Ext(f.pattern)
+	stem := strings.TrimSuffix(f.pattern, ext)
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	for _, loc := range rotationPlaceholder.FindAllStringIndex(stem, -1) {
+		b.WriteString(regexp.QuoteMeta(stem[last:loc[0]]))
+		switch stem[loc[0]:loc[1]] {
+		case "{date}":
+			b.WriteString(`\d{4}-\d{2}-\d{2}`)
+		case "{time}":
+			b.WriteString(`\d{2}-\d{2}`)
+		}
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(stem[last:]))
+	b.WriteString(`(-\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2}\.\d{3})?`)
+	b.WriteString(regexp.QuoteMeta(ext))
+	b.WriteString(`(\.gz)?$`)
+	return regexp.MustCompile(b.String())
+}
+
+// compressFile gzips name into name.gz, keeping its modification time, and removes name.
+func compressFile(name string) (err error) {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	stat, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(name+compressSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, logFilePermission)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err == nil {
+		err = gz.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chtimes(name+compressSuffix, stat.ModTime(), stat.ModTime())
+	}
+	if err != nil {
+		_ = os.Remove(name + compressSuffix)
+		return err
+	}
+	return os.Remove(name)
+}
+
+func (f *rotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Sync()
+}
+
+// Close closes the active file and waits for the cleanup of old files.
+// A later Write opens the file again, but no longer rotates it, so that no
+// cleanup runs after Close.
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	f.closed = true
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mu.Unlock()
+
+	f.cleanup.Wait()
+	if errors.Is(err, os.ErrClosed) {
+		return nil
+	}
+	return err
+}
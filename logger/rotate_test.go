@@ -0,0 +1,300 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newRotationTestLogger returns a logger writing JSON files into dir with rotation.
+func newRotationTestLogger(t *testing.T, filePath string, clock *testClock, rotation Rotation, opts ...Option) *Logger {
+	t.Helper()
+	opts = append([]Option{
+		WithLevel(LevelDebug),
+		WithDev(false),
+		WithFileOutput(filePath),
+		WithClock(clock),
+		WithRotation(rotation),
+	}, opts...)
+	logger, err := newLogger(opts...)
+	if err != nil {
+		t.Fatalf("newLogger() error = %v", err)
+	}
+	return logger
+}
+
+// dirFiles returns the sorted file names of dir.
+func dirFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readFile(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRotation_Daily(t *testing.T) {
+	dir := t.TempDir()
+	clock := newTestClock()
+	logger := newRotationTestLogger(t, filepath.Join(dir, "svc.log"), clock, Rotation{Interval: 24 * time.Hour})
+
+	logger.Info("first day")
+	clock.Add(11 * time.Hour) // 23:00
+	logger.Info("still first day")
+	clock.Add(time.Hour)
+	logger.Info("second day")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	want := []string{"svc-2026-10-17.log", "svc-2026-10-18.log"}
+	if got := dirFiles(t, dir); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	if got := strings.Count(readFile(t, filepath.Join(dir, want[0])), "\n"); got != 2 {
+		t.Errorf("%s has %d lines, want 2", want[0], got)
+	}
+	if got := readFile(t, filepath.Join(dir, want[1])); !strings.Contains(got, "second day") {
+		t.Errorf("%s = %q, want the second day entry", want[1], got)
+	}
+}
+
+func TestRotation_Pattern(t *testing.T) {
+	dir := t.TempDir()
+	clock := newTestClock()
+	logger := newRotationTestLogger(t, dir, clock, Rotation{
+		Interval: time.Hour,
+		Pattern:  "{service}_{date}_{time}.jsonl",
+		Service:  "api",
+	})
+
+	logger.Info("noon")
+	clock.Add(90 * time.Minute)
+	logger.Info("half past one")
+	_ = logger.Close()
+
+	want := []string{"api_2026-10-17_12-00.jsonl", "api_2026-10-17_13-00.jsonl"}
+	if got := dirFiles(t, dir); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+}
+
+func TestRotation_Levels(t *testing.T) {
+	dir := t.TempDir()
+	clock := newTestClock()
+	logger := newRotationTestLogger(t, dir, clock, Rotation{
+		Interval: 24 * time.Hour,
+		Levels: map[string]string{
+			LevelWarn:  "{service}-{level}-{date}.log",
+			LevelError: "error.log",
+		},
+	})
+
+	logger.Info("request served")
+	logger.Warn("slow request")
+	logger.Error("request failed")
+	_ = logger.Close()
+
+	want := []string{"app-2026-10-17.log", "app-warn-2026-10-17.log", "error.log"}
+	if got := dirFiles(t, dir); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	tests := []struct {
+		file  string
+		lines int
+	}{
+		{"app-2026-10-17.log", 3},
+		{"app-warn-2026-10-17.log", 2},
+		{"error.log", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			if got := strings.Count(readFile(t, filepath.Join(dir, tt.file)), "\n"); got != tt.lines {
+				t.Errorf("%s has %d lines, want %d", tt.file, got, tt.lines)
+			}
+		})
+	}
+}
+
+func TestRotation_StaticNameRenamed(t *testing.T) {
+	dir := t.TempDir()
+	clock := newTestClock()
+	logger := newRotationTestLogger(t, dir, clock, Rotation{
+		Interval: 24 * time.Hour,
+		Levels:   map[string]string{LevelError: "error.log"},
+	})
+
+	logger.Error("day one")
+	clock.Add(24 * time.Hour)
+	logger.Error("day two")
+	_ = logger.Close()
+
+	backup := "error-2026-10-18T12-00-00.000.log"
+	files := dirFiles(t, dir)
+	if !contains(files, backup) || !contains(files, "error.log") {
+		t.Fatalf("files = %v, want error.log and %s", files, backup)
+	}
+	if got := readFile(t, filepath.Join(dir, backup)); !strings.Contains(got, "day one") {
+		t.Errorf("%s = %q, want the day one entry", backup, got)
+	}
+}
+
+func TestRotation_Size(t *testing.T) {
+	dir := t.TempDir()
+	clock := newTestClock()
+	logger := newRotationTestLogger(t, dir, clock, Rotation{MaxSize: 1})
+
+	large := strings.Repeat("x", 600*1024)
+	logger.Info(large)
+	clock.Add(time.Second)
+	logger.Info(large)
+	_ = logger.Close()
+
+	want := []string{"app-2026-10-17T12-00-01.000.log", "app.log"}
+	if got := dirFiles(t, dir); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+}
+
+func TestRotation_Retention(t *testing.T) {
+	dir := t.TempDir()
+	clock := newTestClock()
+	logger := newRotationTestLogger(t, dir, clock, Rotation{Interval: 24 * time.Hour},
+		WithMaxBackups(2),
+		WithCompress(true),
+	)
+
+	for i := 0; i < 4; i++ {
+		logger.Info("daily entry")
+		clock.Add(24 * time.Hour)
+	}
+	_ = logger.Close()
+
+	want := []string{"app-2026-10-18.log.gz", "app-2026-10-19.log.gz", "app-2026-10-20.log"}
+	if got := dirFiles(t, dir); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+}
+
+func TestRotation_MaxAge(t *testing.T) {
+	dir := t.TempDir()
+	clock := newTestClock()
+	expired := filepath.Join(dir, "app-2026-09-01.log")
+	if err := os.WriteFile(expired, []byte("{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(expired, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := newRotationTestLogger(t, dir, clock, Rotation{Interval: 24 * time.Hour}, WithMaxAge(30))
+	logger.Info("today")
+	clock.Add(24 * time.Hour)
+	logger.Info("tomorrow")
+	_ = logger.Close()
+
+	want := []string{"app-2026-10-17.log", "app-2026-10-18.log"}
+	if got := dirFiles(t, dir); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+}
+
+func TestRotation_NoRotationAfterClose(t *testing.T) {
+	dir := t.TempDir()
+	clock := newTestClock()
+	logger := newRotationTestLogger(t, dir, clock, Rotation{Interval: 24 * time.Hour}, WithMaxBackups(1))
+
+	logger.Info("first day")
+	clock.Add(24 * time.Hour)
+	logger.Info("second day")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// A late entry reopens a file, which is not rotated: no cleanup runs
+	// after Close, so the first day is not removed.
+	clock.Add(24 * time.Hour)
+	logger.Info("late")
+	clock.Add(24 * time.Hour)
+	logger.Info("later")
+
+	want := []string{"app-2026-10-17.log", "app-2026-10-18.log", "app-2026-10-19.log"}
+	if got := dirFiles(t, dir); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	if got := strings.Count(readFile(t, filepath.Join(dir, want[2])), "\n"); got != 2 {
+		t.Errorf("%s has %d lines, want 2", want[2], got)
+	}
+}
+
+func TestRotation_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		rotation Rotation
+		wantErr  bool
+	}{
+		{name: "daily", rotation: Rotation{Interval: 24 * time.Hour}, wantErr: false},
+		{name: "levels", rotation: Rotation{Levels: map[string]string{LevelError: "{level}.log"}}, wantErr: false},
+		{name: "negative interval", rotation: Rotation{Interval: -time.Hour}, wantErr: true},
+		{name: "unknown placeholder", rotation: Rotation{Pattern: "{host}.log"}, wantErr: true},
+		{name: "level in main pattern", rotation: Rotation{Pattern: "{level}.log"}, wantErr: true},
+		{name: "directory in pattern", rotation: Rotation{Pattern: "logs/app.log"}, wantErr: true},
+		{name: "invalid level", rotation: Rotation{Levels: map[string]string{"loud": "loud.log"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newConfig(WithFileOutput(t.TempDir()), WithRotation(tt.rotation))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRotation_PeriodStart(t *testing.T) {
+	at := time.Date(2026, 10, 17, 14, 35, 0, 0, time.UTC)
+	tests := []struct {
+		interval time.Duration
+		want     time.Time
+	}{
+		{time.Hour, time.Date(2026, 10, 17, 14, 0, 0, 0, time.UTC)},
+		{15 * time.Minute, time.Date(2026, 10, 17, 14, 30, 0, 0, time.UTC)},
+		{24 * time.Hour, time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)},
+		{48 * time.Hour, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+		{7 * 24 * time.Hour, time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.interval.String(), func(t *testing.T) {
+			if got := (Rotation{Interval: tt.interval}).periodStart(at); !got.Equal(tt.want) {
+				t.Errorf("periodStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
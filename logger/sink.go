@@ -0,0 +1,230 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/laziness-coders/go-utils/generic"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.uber.org/zap/zapcore"
+)
+
+// Sink defines one log output with its own encoder, level threshold and writer.
+// Several sinks can be combined, e.g. text to stdout and JSON to a rotated file.
+type Sink struct {
+	Format   string    // json, text, logfmt, pretty (default: json)
+	Level    string    // minimum level of this sink (default: debug, i.e. only the logger level applies)
+	Output   string    // stdout, stderr, file, writer, otel, core (default: stdout)
+	FilePath string    // file or directory path (only for file output)
+	Writer   io.Writer // destination (only for writer output)
+
+	// Core receives the entries of a core output, e.g. zap's observer in
+	// tests. Format and Level do not apply to it.
+	Core zapcore.Core
+
+	// LoggerProvider receives the records of an otel output (default: the
+	// global provider). Format does not apply to otel outputs.
+	LoggerProvider otellog.LoggerProvider
+
+	// Rotation settings for file output, default to the Config values.
+	MaxSize    int   // max size in MB before rotation
+	MaxAge     int   // max days to retain old logs
+	MaxBackups int   // max number of old log files
+	Compress   *bool // compress rotated files; nil uses Config.Compress
+
+	// Rotation rotates file output by time and size with named files
+	// (default: Config.Rotation). Nil rotates by size only, keeping the
+	// FilePath name (app.log in a directory).
+	Rotation *Rotation
+}
+
+// legacySink describes the single output configured by Format and FilePath.
+func (c Config) legacySink() Sink {
+	sink := Sink{
+		Format:     c.Format,
+		Output:     OutputStdout,
+		MaxSize:    c.MaxSize,
+		MaxAge:     c.MaxAge,
+		MaxBackups: c.MaxBackups,
+		Compress:   generic.ToPointer(c.Compress),
+	}
+	if c.Format == FormatFile {
+		sink.Format = FormatJSON
+		sink.Output = OutputFile
+		sink.FilePath = c.FilePath
+	}
+	return sink
+}
+
+// setDefaults fills the zero values of the sink, taking rotation settings from cfg.
+func (s Sink) setDefaults(cfg Config) Sink {
+	if s.Format == "" {
+		s.Format = FormatJSON
+	}
+	if s.Level == "" {
+		s.Level = LevelDebug
+	}
+	if s.Output == "" {
+		s.Output = OutputStdout
+	}
+	if s.MaxSize <= 0 {
+		s.MaxSize = cfg.MaxSize
+	}
+	if s.MaxAge <= 0 {
+		s.MaxAge = cfg.MaxAge
+	}
+	if s.MaxBackups <= 0 {
+		s.MaxBackups = cfg.MaxBackups
+	}
+	if s.Compress == nil {
+		s.Compress = generic.ToPointer(cfg.Compress)
+	}
+	if s.Rotation == nil {
+		s.Rotation = cfg.Rotation
+	}
+	if s.Rotation != nil && s.Output == OutputFile {
+		rotation := s.Rotation.setDefaults(s)
+		s.Rotation = &rotation
+	}
+	return s
+}
+
+// compress reports whether rotated files of the sink are compressed.
+func (s Sink) compress() bool {
+	return s.Compress != nil && *s.Compress
+}
+
+// validate checks if the sink is valid, reporting all invalid fields at once.
+func (s Sink) validate() error {
+	var errs []error
+	switch s.Format {
+	case FormatJSON, FormatText, FormatLogfmt, FormatPretty:
+		// valid
+	default:
+		errs = append(errs, fmt.Errorf("invalid sink format: %s", s.Format))
+	}
+
+	if _, err := parseLevel(s.Level); err != nil {
+		errs = append(errs, err)
+	}
+
+	switch s.Output {
+	case OutputStdout, OutputStderr, OutputOTel:
+		// valid
+	case OutputFile:
+		if s.FilePath == "" {
+			errs = append(errs, fmt.Errorf("file path is required for file output"))
+			break
+		}
+		dir := filepath.Dir(s.FilePath)
+		if s.Rotation != nil {
+			if err := s.Rotation.validate(); err != nil {
+				errs = append(errs, err)
+			}
+			dir, _ = rotationPath(s.FilePath)
+		}
+		if err := os.MkdirAll(dir, logDirPermission); err != nil {
+			errs = append(errs, fmt.Errorf("failed to create log directory %s: %w", dir, err))
+		}
+	case OutputWriter:
+		if s.Writer == nil {
+			errs = append(errs, fmt.Errorf("writer is required for writer output"))
+		}
+	case OutputCore:
+		if s.Core == nil {
+			errs = append(errs, fmt.Errorf("core is required for core output"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("invalid sink output: %s", s.Output))
+	}
+
+	return errors.Join(errs...)
+}
+
+// buildWriteSyncer creates the writer of the sink, registering the
+// resources to release in res.
+func (s Sink) buildWriteSyncer(clock zapcore.Clock, res *outputs) zapcore.WriteSyncer {
+	switch s.Output {
+	case OutputStderr:
+		return consoleSyncer{os.Stderr}
+	case OutputFile:
+		if s.Rotation != nil {
+			file := newRotatingFile(s, "", zapcore.InvalidLevel, clock)
+			res.add(file.Close)
+			return file
+		}
+		file := createFileWriter(s)
+		res.add(file.Close)
+		return zapcore.AddSync(file)
+	case OutputWriter:
+		return zapcore.AddSync(s.Writer)
+	default:
+		return consoleSyncer{os.Stdout}
+	}
+}
+
+// colored reports whether the sink writes colors: only stdout and stderr
+// outputs do, when they are terminals.
+func (s Sink) colored() bool {
+	switch s.Output {
+	case OutputStdout:
+		return colorEnabled(os.Stdout)
+	case OutputStderr:
+		return colorEnabled(os.Stderr)
+	default:
+		return false
+	}
+}
+
+// buildCore creates the zap core of the sink, encoding with cfg's encoder
+// settings and buffering if configured. File outputs with rotation levels
+// get a core per level file.
+func (s Sink) buildCore(cfg Config, res *outputs) zapcore.Core {
+	if s.Output == OutputCore {
+		return s.Core
+	}
+
+	// Sink levels are checked by validate.
+	level, _ := parseLevel(s.Level)
+	if s.Output == OutputOTel {
+		var r *redactor
+		if cfg.Redaction != nil {
+			r = newRedactor(*cfg.Redaction)
+		}
+		return newOTelCore(s.LoggerProvider, level, r)
+	}
+
+	core := zapcore.NewCore(
+		cfg.buildZapEncoder(s.Format, s.colored()),
+		cfg.buffer(s.buildWriteSyncer(cfg.Clock, res), res),
+		level,
+	)
+	if s.Output != OutputFile || s.Rotation == nil || len(s.Rotation.Levels) == 0 {
+		return core
+	}
+
+	cores := []zapcore.Core{core}
+	for _, route := range s.Rotation.routes() {
+		file := newRotatingFile(s, route.pattern, route.level, cfg.Clock)
+		res.add(file.Close)
+		cores = append(cores, zapcore.NewCore(
+			cfg.buildZapEncoder(s.Format, s.colored()),
+			cfg.buffer(file, res),
+			max(level, route.level),
+		))
+	}
+	return zapcore.NewTee(cores...)
+}
+
+// buffer wraps ws with a buffer if configured.
+func (c Config) buffer(ws zapcore.WriteSyncer, res *outputs) zapcore.WriteSyncer {
+	if c.Buffering == nil {
+		return ws
+	}
+	buffered := newBufferedWriteSyncer(ws, *c.Buffering, c.Clock)
+	res.add(buffered.stop)
+	return buffered
+}
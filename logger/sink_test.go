@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/laziness-coders/go-utils/generic"
+	"go.uber.org/zap/zapcore"
+)
+
+// syncBuffer is a bytes.Buffer that records Sync calls.
+type syncBuffer struct {
+	bytes.Buffer
+	synced int
+}
+
+func (b *syncBuffer) Sync() error {
+	b.synced++
+	return nil
+}
+
+func TestLogger_Sinks(t *testing.T) {
+	text := &syncBuffer{}
+	jsonOut := &syncBuffer{}
+
+	logger, err := newLogger(
+		WithLevel(LevelDebug),
+		WithDev(false),
+		WithSinks(
+			Sink{Format: FormatText, Output: OutputWriter, Writer: text},
+			Sink{Format: FormatJSON, Level: LevelWarn, Output: OutputWriter, Writer: jsonOut},
+		),
+	)
+	if err != nil {
+		t.Fatalf("newLogger() error = %v", err)
+	}
+
+	logger.Debug("debug message")
+	logger.Warn("warn message")
+
+	if got := strings.Count(text.String(), "\n"); got != 2 {
+		t.Errorf("text sink got %d lines, want 2:\n%s", got, text.String())
+	}
+	if !strings.Contains(text.String(), "\tDEBUG\t") {
+		t.Errorf("text sink should use the console encoder:\n%s", text.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(jsonOut.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("json sink got %d lines, want 1:\n%s", len(lines), jsonOut.String())
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("json sink line is not JSON: %v", err)
+	}
+	if entry["msg"] != "warn message" {
+		t.Errorf("json sink msg = %v, want %q", entry["msg"], "warn message")
+	}
+
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if text.synced != 1 || jsonOut.synced != 1 {
+		t.Errorf("Sync() should flush every sink, got text=%d json=%d", text.synced, jsonOut.synced)
+	}
+}
+
+func TestLogger_SinksRespectLoggerLevel(t *testing.T) {
+	out := &syncBuffer{}
+	logger, err := newLogger(
+		WithLevel(LevelWarn),
+		WithSink(Sink{Output: OutputWriter, Writer: out}),
+	)
+	if err != nil {
+		t.Fatalf("newLogger() error = %v", err)
+	}
+
+	logger.Info("filtered by logger level")
+	logger.Named("payments").Info("still filtered")
+	_ = logger.SetLevelOverride("payments", LevelDebug)
+	logger.Named("payments").Info("enabled by override")
+
+	if got := strings.Count(out.String(), "\n"); got != 1 {
+		t.Errorf("got %d lines, want 1:\n%s", got, out.String())
+	}
+}
+
+func TestLogger_FileSink(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "nested", "app.json.log")
+	out := &syncBuffer{}
+
+	logger, err := newLogger(
+		WithLevel(LevelInfo),
+		WithSinks(
+			Sink{Format: FormatText, Output: OutputWriter, Writer: out},
+			Sink{Format: FormatJSON, Output: OutputFile, FilePath: logFile},
+		),
+	)
+	if err != nil {
+		t.Fatalf("newLogger() error = %v", err)
+	}
+	logger.Info("to both sinks")
+	_ = logger.Sync()
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(data), `"msg":"to both sinks"`) {
+		t.Errorf("file sink content = %s", data)
+	}
+	if !strings.Contains(out.String(), "to both sinks") {
+		t.Errorf("writer sink content = %s", out.String())
+	}
+}
+
+func TestSink_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		sink    Sink
+		wantErr bool
+	}{
+		{name: "stdout", sink: Sink{}, wantErr: false},
+		{name: "stderr text", sink: Sink{Format: FormatText, Output: OutputStderr}, wantErr: false},
+		{name: "writer", sink: Sink{Output: OutputWriter, Writer: &bytes.Buffer{}}, wantErr: false},
+		{name: "writer without writer", sink: Sink{Output: OutputWriter}, wantErr: true},
+		{name: "core", sink: Sink{Output: OutputCore, Core: zapcore.NewNopCore()}, wantErr: false},
+		{name: "core without core", sink: Sink{Output: OutputCore}, wantErr: true},
+		{name: "file without path", sink: Sink{Output: OutputFile}, wantErr: true},
+		{name: "invalid format", sink: Sink{Format: FormatFile}, wantErr: true},
+		{name: "invalid level", sink: Sink{Level: "loud"}, wantErr: true},
+		{name: "invalid output", sink: Sink{Output: "syslog"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newConfig(WithSink(tt.sink))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSink_SetDefaultsFromConfig(t *testing.T) {
+	cfg := Config{MaxSize: 50, MaxAge: 14, MaxBackups: 3, Compress: true}
+
+	tests := []struct {
+		name         string
+		sink         Sink
+		want         Sink
+		wantCompress bool
+	}{
+		{
+			name:         "inherited",
+			sink:         Sink{Output: OutputFile, FilePath: "app.log"},
+			want:         Sink{MaxSize: 50, MaxAge: 14, MaxBackups: 3},
+			wantCompress: true,
+		},
+		{
+			name:         "set on the sink",
+			sink:         Sink{Output: OutputFile, FilePath: "app.log", MaxSize: 10, MaxAge: 1, MaxBackups: 1},
+			want:         Sink{MaxSize: 10, MaxAge: 1, MaxBackups: 1},
+			wantCompress: true,
+		},
+		{
+			name:         "compression turned off",
+			sink:         Sink{Output: OutputFile, FilePath: "app.log", Compress: generic.ToPointer(false)},
+			want:         Sink{MaxSize: 50, MaxAge: 14, MaxBackups: 3},
+			wantCompress: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.sink.setDefaults(cfg)
+			if got.MaxSize != tt.want.MaxSize || got.MaxAge != tt.want.MaxAge ||
+				got.MaxBackups != tt.want.MaxBackups || got.compress() != tt.wantCompress {
+				t.Errorf("setDefaults() = MaxSize %d, MaxAge %d, MaxBackups %d, Compress %v, want %d, %d, %d, %v",
+					got.MaxSize, got.MaxAge, got.MaxBackups, got.compress(),
+					tt.want.MaxSize, tt.want.MaxAge, tt.want.MaxBackups, tt.wantCompress)
+			}
+		})
+	}
+}
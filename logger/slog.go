@@ -0,0 +1,180 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// slogHandler writes slog records through a Logger.
+type slogHandler struct {
+	logger *Logger
+	fields []zap.Field // fields of WithAttrs, including group namespaces
+	groups []string    // groups opened by WithGroup, not yet followed by attributes
+}
+
+// SlogHandler returns a slog.Handler writing records through l, with l's
+// level (overrides included), outputs and name. Records get the caller of
+// the slog call, and the trace and context fields of their context, as with Ctx.
+// Usage:
+//
+//	slog.SetDefault(slog.New(logger.GetLogger().SlogHandler()))
+//	slog.InfoContext(ctx, "order created", "order_id", id)
+func (l *Logger) SlogHandler() slog.Handler {
+	return &slogHandler{logger: l}
+}
+
+// Slog returns a slog.Logger writing through l, see SlogHandler.
+func (l *Logger) Slog() *slog.Logger {
+	return slog.New(l.SlogHandler())
+}
+
+// slogLevel maps slog levels to zap levels. Levels between the named slog
+// levels round down, levels above error stay at error.
+func slogLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case level < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case level < slog.LevelError:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+// enabled reports whether l logs at lvl, honoring the override of its name.
+// Loggers without level control, e.g. wrapping a zap.Logger, ask their core only.
+func (l *Logger) enabled(lvl zapcore.Level) bool {
+	if l.level == nil {
+		return l.Core().Enabled(lvl)
+	}
+	return l.Core().Enabled(lvl) && lvl >= l.level.levelFor(l.Name())
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.enabled(slogLevel(level))
+}
+
+func (h *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	ent := zapcore.Entry{
+		Level:      slogLevel(record.Level),
+		Time:       record.Time,
+		LoggerName: h.logger.Name(),
+		Message:    record.Message,
+	}
+	if ent.Time.IsZero() {
+		ent.Time = time.Now()
+	}
+	if record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		ent.Caller = zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, true)
+		ent.Caller.Function = frame.Function
+	}
+	ce := h.logger.Core().Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+
+	// Context fields come first, so they stay out of the handler's groups.
+	fields := extractContextFields(ctx, h.logger.trace)
+	if f, ok := spanContextField(ctx); ok {
+		fields = append(fields, f)
+	}
+	fields = append(fields, h.fields...)
+	if record.NumAttrs() > 0 {
+		for _, g := range h.groups {
+			fields = append(fields, zap.Namespace(g))
+		}
+		record.Attrs(func(attr slog.Attr) bool {
+			fields = appendSlogAttr(fields, attr)
+			return true
+		})
+	}
+	ce.Write(fields...)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	fields := append([]zap.Field(nil), h.fields...)
+	for _, g := range h.groups {
+		fields = append(fields, zap.Namespace(g))
+	}
+	for _, attr := range attrs {
+		fields = appendSlogAttr(fields, attr)
+	}
+	return &slogHandler{logger: h.logger, fields: fields}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	groups := append(append([]string(nil), h.groups...), name)
+	return &slogHandler{logger: h.logger, fields: h.fields, groups: groups}
+}
+
+// appendSlogAttr converts attr to zap fields, following the slog.Handler
+// rules: empty attributes and groups are dropped, groups without a key are inlined.
+func appendSlogAttr(fields []zap.Field, attr slog.Attr) []zap.Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		if len(group) == 0 {
+			return fields
+		}
+		if attr.Key == "" {
+			for _, a := range group {
+				fields = appendSlogAttr(fields, a)
+			}
+			return fields
+		}
+		return append(fields, zap.Object(attr.Key, slogGroup(group)))
+	case slog.KindString:
+		return append(fields, zap.String(attr.Key, attr.Value.String()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(attr.Key, attr.Value.Int64()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(attr.Key, attr.Value.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(attr.Key, attr.Value.Float64()))
+	case slog.KindBool:
+		return append(fields, zap.Bool(attr.Key, attr.Value.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Duration(attr.Key, attr.Value.Duration()))
+	case slog.KindTime:
+		return append(fields, zap.Time(attr.Key, attr.Value.Time()))
+	default:
+		if err, ok := attr.Value.Any().(error); ok {
+			return append(fields, zap.NamedError(attr.Key, err))
+		}
+		return append(fields, zap.Any(attr.Key, attr.Value.Any()))
+	}
+}
+
+// slogGroup encodes the attributes of a group as an object.
+type slogGroup []slog.Attr
+
+func (g slogGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	var fields []zap.Field
+	for _, attr := range g {
+		fields = appendSlogAttr(fields, attr)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// callerHere returns the short caller of the line after the call.
+func callerHere() string {
+	_, file, line, _ := runtime.Caller(1)
+	return fmt.Sprintf("%s/%s:%d", filepath.Base(filepath.Dir(file)), filepath.Base(file), line+1)
+}
+
+func TestSlogLevel(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  string
+	}{
+		{slog.LevelDebug - 4, LevelDebug},
+		{slog.LevelDebug, LevelDebug},
+		{slog.LevelInfo, LevelInfo},
+		{slog.LevelInfo + 2, LevelInfo},
+		{slog.LevelWarn, LevelWarn},
+		{slog.LevelError, LevelError},
+		{slog.LevelError + 4, LevelError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			if got := slogLevel(tt.level).String(); got != tt.want {
+				t.Errorf("slogLevel(%v) = %s, want %s", tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogger_SlogHandler(t *testing.T) {
+	logger, out := newJSONTestLogger(t, WithLevel(LevelInfo))
+	slogger := logger.Named("lib").Slog()
+
+	if slogger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("debug should be disabled at info level")
+	}
+	slogger.Debug("dropped")
+
+	ctx := testSpanContext(t)
+	caller := callerHere()
+	slogger.WarnContext(ctx, "slow query", "table", "users", "rows", 1200, "took", time.Second,
+		"err", errors.New("timeout"))
+
+	entries := decodeLines(t, out)
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1: %v", len(entries), entries)
+	}
+	entry := entries[0]
+	want := map[string]interface{}{
+		"level":    "WARN",
+		"logger":   "lib",
+		"msg":      "slow query",
+		"caller":   caller,
+		"table":    "users",
+		"rows":     float64(1200),
+		"took":     float64(1),
+		"err":      "timeout",
+		"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":  "00f067aa0ba902b7",
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("%s = %v, want %v", k, entry[k], v)
+		}
+	}
+}
+
+func TestLogger_EnabledWithoutLevelControl(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	logger := &Logger{Logger: zap.New(core)}
+
+	slogger := logger.Slog()
+	if slogger.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("slog: info should be disabled by the core")
+	}
+	if !slogger.Enabled(context.Background(), slog.LevelWarn) {
+		t.Error("slog: warn should be enabled by the core")
+	}
+	if logger.Logr().Enabled() {
+		t.Error("logr: info should be disabled by the core")
+	}
+	slogger.Warn("written")
+	if logs.Len() != 1 {
+		t.Errorf("got %d entries, want 1", logs.Len())
+	}
+}
+
+func TestLogger_SlogGroups(t *testing.T) {
+	logger, out := newJSONTestLogger(t)
+	slogger := logger.Slog()
+	ctx := testSpanContext(t)
+
+	slogger.With("service", "api").WithGroup("req").With("method", "GET").
+		InfoContext(ctx, "grouped", "status", 200, slog.Group("user", "id", 7), slog.Group("empty"))
+	slogger.WithGroup("unused").Info("no attrs")
+
+	entries := decodeLines(t, out)
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+
+	got := entries[0]
+	if got["service"] != "api" {
+		t.Errorf("service = %v, want api", got["service"])
+	}
+	if got["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace fields should stay at the top level: %v", got)
+	}
+	req, ok := got["req"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("req = %v, want an object", got["req"])
+	}
+	if req["method"] != "GET" || req["status"] != float64(200) {
+		t.Errorf("req = %v, want method and status", req)
+	}
+	if user, _ := req["user"].(map[string]interface{}); user["id"] != float64(7) {
+		t.Errorf("req.user = %v, want id 7", req["user"])
+	}
+	if _, ok := req["empty"]; ok {
+		t.Errorf("empty groups should be dropped: %v", req)
+	}
+
+	if _, ok := entries[1]["unused"]; ok {
+		t.Errorf("groups without attributes should be dropped: %v", entries[1])
+	}
+}
@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"log"
+
+	"go.uber.org/zap"
+)
+
+// StdLog returns a standard library logger writing each line through l at
+// level, with the caller of the log call.
+func (l *Logger) StdLog(level string) (*log.Logger, error) {
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	return zap.NewStdLogAt(l.stdLogBase(), lvl)
+}
+
+// RedirectStdLog sends the output of the standard library's global logger,
+// e.g. log.Printf, through l at level. Call restore to undo it.
+// Usage:
+//
+//	restore, err := logger.GetLogger().RedirectStdLog(logger.LevelInfo)
+//	defer restore()
+func (l *Logger) RedirectStdLog(level string) (restore func(), err error) {
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	return zap.RedirectStdLogAt(l.stdLogBase(), lvl)
+}
+
+// stdLogBase undoes the configured caller skip, which accounts for the
+// package-level functions, as zap skips the log package frames itself.
+func (l *Logger) stdLogBase() *zap.Logger {
+	return l.Logger.WithOptions(zap.AddCallerSkip(-l.callerSkip))
+}
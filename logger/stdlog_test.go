@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"log"
+	"testing"
+)
+
+func TestLogger_StdLog(t *testing.T) {
+	logger, out := newJSONTestLogger(t)
+	std, err := logger.StdLog(LevelWarn)
+	if err != nil {
+		t.Fatalf("StdLog() error = %v", err)
+	}
+	caller := callerHere()
+	std.Printf("disk %d%% full", 91)
+
+	entry := decodeLastLine(t, out)
+	if entry["level"] != "WARN" || entry["msg"] != "disk 91% full" || entry["caller"] != caller {
+		t.Errorf("entry = %v, want a warn entry from %s", entry, caller)
+	}
+
+	if _, err := logger.StdLog("loud"); err == nil {
+		t.Error("StdLog() with an invalid level should fail")
+	}
+}
+
+func TestLogger_RedirectStdLog(t *testing.T) {
+	logger, out := newJSONTestLogger(t)
+	restore, err := logger.RedirectStdLog(LevelInfo)
+	if err != nil {
+		t.Fatalf("RedirectStdLog() error = %v", err)
+	}
+	caller := callerHere()
+	log.Println("from the log package")
+	restore()
+
+	entry := decodeLastLine(t, out)
+	if entry["level"] != "INFO" || entry["msg"] != "from the log package" || entry["caller"] != caller {
+		t.Errorf("entry = %v, want an info entry from %s", entry, caller)
+	}
+}
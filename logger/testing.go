@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestOutput is the output captured by ReplaceForTest. It is safe for
+// concurrent use.
+type TestOutput struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (o *TestOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.buf.Write(p)
+}
+
+// String returns the captured output.
+func (o *TestOutput) String() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.buf.String()
+}
+
+// Lines returns the captured lines, i.e. one JSON entry per line by default.
+func (o *TestOutput) Lines() []string {
+	out := strings.TrimSpace(o.String())
+	if out == "" {
+		return nil
+	}
+	return strings.Split(out, "\n")
+}
+
+// Reset discards the captured output.
+func (o *TestOutput) Reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.buf.Reset()
+}
+
+// ReplaceForTest replaces the global logger with one writing JSON entries
+// at debug level to the returned TestOutput, and restores the previous
+// global logger when the test ends. opts are applied after these defaults.
+// The output is reported with t.Log if the test fails.
+// Tests calling it must not run in parallel with other tests using the global logger.
+func ReplaceForTest(t testing.TB, opts ...Option) *TestOutput {
+	t.Helper()
+	out := &TestOutput{}
+	opts = append([]Option{
+		WithLevel(LevelDebug),
+		WithDev(false),
+		WithSink(Sink{Format: FormatJSON, Output: OutputWriter, Writer: out}),
+	}, opts...)
+	logger, err := newLogger(opts...)
+	if err != nil {
+		t.Fatalf("logger.ReplaceForTest: %v", err)
+	}
+
+	restore := swapGlobal(logger, true)
+	t.Cleanup(func() {
+		restore()
+		_ = logger.Close()
+		if t.Failed() && out.String() != "" {
+			t.Logf("captured log output:\n%s", out.String())
+		}
+	})
+	return out
+}
@@ -9,6 +9,12 @@ import (
 // Logger embeds zap.Logger to inherit all standard methods.
 type Logger struct {
 	*zap.Logger
+	level   *levelControl
+	trace   TraceCorrelation
+	outputs *outputs
+	// callerSkip is Config.CallerSkip, undone by adapters that wrap zap's
+	// own caller skipping.
+	callerSkip int
 }
 
 // SugaredLogger embeds zap.SugaredLogger with tracing support.
@@ -17,24 +23,77 @@ type SugaredLogger struct {
 	logger *Logger
 }
 
+// New creates a Logger with the given options, independent of the global logger.
+func New(opts ...Option) (*Logger, error) {
+	return newLogger(opts...)
+}
+
 // newLogger creates a new Logger instance with the given configuration.
 func newLogger(opts ...Option) (*Logger, error) {
 	cfg, err := newConfig(opts...)
 	if err != nil {
 		return nil, err
 	}
+	return newLoggerFromConfig(cfg), nil
+}
+
+// newLoggerFromConfig creates a Logger from a validated configuration.
+func newLoggerFromConfig(cfg *Config) *Logger {
+	level := newLevelControl(cfg.AtomicLevel, cfg.LevelOverrides)
+	zapLogger, res := cfg.buildZapLogger(level)
+	return &Logger{
+		Logger:     zapLogger,
+		level:      level,
+		trace:      cfg.TraceCorrelation,
+		outputs:    res,
+		callerSkip: cfg.CallerSkip,
+	}
+}
+
+// derive wraps a zap logger built from l, keeping l's level control and outputs.
+func (l *Logger) derive(zl *zap.Logger) *Logger {
 	return &Logger{
-		Logger: cfg.buildZapLogger(),
-	}, nil
+		Logger:     zl,
+		level:      l.level,
+		trace:      l.trace,
+		outputs:    l.outputs,
+		callerSkip: l.callerSkip,
+	}
 }
 
 // Trace extracts tracing fields from context and returns a logger with those fields.
+// The fields follow the logger's TraceCorrelation formats.
 // Usage: logger.Trace(ctx).Info("processing request")
 func (l *Logger) Trace(ctx context.Context) *Logger {
-	fields := ExtractTraceFields(ctx)
-	return &Logger{
-		Logger: l.With(fields...),
+	fields := ExtractTraceFieldsWith(ctx, l.trace)
+	if f, ok := spanContextField(ctx); ok {
+		fields = append(fields, f)
 	}
+	return l.With(fields...)
+}
+
+// Ctx returns a logger with the trace fields, the fields added with NewContext
+// and the fields of the registered ContextExtractors.
+// Usage: logger.Ctx(ctx).Info("processing request")
+func (l *Logger) Ctx(ctx context.Context) *Logger {
+	fields := extractContextFields(ctx, l.trace)
+	if f, ok := spanContextField(ctx); ok {
+		fields = append(fields, f)
+	}
+	return l.With(fields...)
+}
+
+// With returns a child logger with the given fields, keeping the level
+// control and trace correlation of l.
+func (l *Logger) With(fields ...zap.Field) *Logger {
+	return l.derive(l.Logger.With(fields...))
+}
+
+// Named returns a child logger with the given name appended to the logger's name,
+// separated by a dot. Its level follows the most specific LevelOverrides entry.
+// Usage: logger.Named("payments").Named("stripe") logs as "payments.stripe".
+func (l *Logger) Named(name string) *Logger {
+	return l.derive(l.Logger.Named(name))
 }
 
 // Sugar returns a SugaredLogger with tracing support.
@@ -82,3 +141,8 @@ func (l *Logger) Fatalf(format string, args ...interface{}) {
 func (sl *SugaredLogger) Trace(ctx context.Context) *SugaredLogger {
 	return sl.logger.Trace(ctx).Sugar()
 }
+
+// Ctx returns a sugared logger with all context fields, see Logger.Ctx.
+func (sl *SugaredLogger) Ctx(ctx context.Context) *SugaredLogger {
+	return sl.logger.Ctx(ctx).Sugar()
+}
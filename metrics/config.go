@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/laziness-coders/go-utils/configs"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// Exporter constants
+const (
+	ExporterPrometheus = "prometheus" // pull: Prometheus text format served by Handler
+	ExporterOTLPHTTP   = "otlphttp"   // push: OTLP over HTTP/protobuf
+	ExporterOTLPGRPC   = "otlpgrpc"   // push: OTLP over gRPC
+	ExporterNone       = "none"       // metrics are recorded but not exported
+)
+
+const defaultInterval = time.Minute
+
+// Config defines the metrics configuration.
+// It can be decoded by configs.ConfigLoader, e.g. as a METRICS section.
+type Config struct {
+	ServiceName           string                 `mapstructure:"SERVICE_NAME"`            // required
+	ServiceVersion        string                 `mapstructure:"SERVICE_VERSION"`         // optional
+	Environment           configs.AppEnvironment `mapstructure:"ENVIRONMENT"`             // default: APP_ENV, then dev
+	Exporter              string                 `mapstructure:"EXPORTER"`                // prometheus, otlphttp, otlpgrpc, none (default: prometheus)
+	Endpoint              string                 `mapstructure:"ENDPOINT"`                // OTLP host:port or URL (default: OTEL_EXPORTER_OTLP_* env)
+	Insecure              bool                   `mapstructure:"INSECURE"`                // disable TLS for OTLP
+	Headers               map[string]string      `mapstructure:"HEADERS"`                 // extra OTLP headers, e.g. API keys
+	Interval              time.Duration          `mapstructure:"INTERVAL"`                // OTLP push interval (default: 1m)
+	DisableRuntimeMetrics bool                   `mapstructure:"DISABLE_RUNTIME_METRICS"` // skip goroutine, GC and memory metrics
+
+	// Reader overrides Exporter with a custom reader, e.g. a TestReader.
+	Reader sdkmetric.Reader `mapstructure:"-"`
+}
+
+// ConfigFromApp returns a Config named and scoped after the application config.
+func ConfigFromApp(app configs.AppConfig) Config {
+	return Config{
+		ServiceName: app.AppName,
+		Environment: app.AppEnv,
+	}
+}
+
+// setDefaults applies default values to the configuration.
+func (c Config) setDefaults() Config {
+	if c.Environment == "" {
+		c.Environment = configs.GetEnv("APP_ENV", string(configs.AppEnvironmentDev))
+	}
+	if c.Exporter == "" {
+		c.Exporter = ExporterPrometheus
+	}
+	if c.Interval <= 0 {
+		c.Interval = defaultInterval
+	}
+	return c
+}
+
+// validate checks if the configuration is valid.
+func (c Config) validate() error {
+	if c.ServiceName == "" {
+		return fmt.Errorf("service name is required")
+	}
+	if c.Reader != nil {
+		return nil
+	}
+	switch c.Exporter {
+	case ExporterPrometheus, ExporterOTLPHTTP, ExporterOTLPGRPC, ExporterNone:
+		// valid
+	default:
+		return fmt.Errorf("invalid exporter: %s", c.Exporter)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// buildReader creates the reader selected by the configuration, and the
+// scrape handler for ExporterPrometheus. It returns a nil reader for ExporterNone.
+func (c Config) buildReader(ctx context.Context) (sdkmetric.Reader, http.Handler, error) {
+	if c.Reader != nil {
+		return c.Reader, nil, nil
+	}
+
+	switch c.Exporter {
+	case ExporterPrometheus:
+		// A dedicated registry keeps the output limited to this provider.
+		registry := prometheus.NewRegistry()
+		reader, err := otelprom.New(otelprom.WithRegisterer(registry))
+		if err != nil {
+			return nil, nil, err
+		}
+		return reader, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}), nil
+	case ExporterOTLPHTTP:
+		exporter, err := otlpmetrichttp.New(ctx, c.otlpHTTPOptions()...)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(c.Interval)), nil, nil
+	case ExporterOTLPGRPC:
+		exporter, err := otlpmetricgrpc.New(ctx, c.otlpGRPCOptions()...)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(c.Interval)), nil, nil
+	case ExporterNone:
+		return nil, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("invalid exporter: %s", c.Exporter)
+	}
+}
+
+func (c Config) otlpHTTPOptions() []otlpmetrichttp.Option {
+	var opts []otlpmetrichttp.Option
+	if c.Endpoint != "" {
+		if strings.Contains(c.Endpoint, "://") {
+			opts = append(opts, otlpmetrichttp.WithEndpointURL(c.Endpoint))
+		} else {
+			opts = append(opts, otlpmetrichttp.WithEndpoint(c.Endpoint))
+		}
+	}
+	if c.Insecure {
+		opts = append(opts, otlpmetrichttp.WithInsecure())
+	}
+	if len(c.Headers) > 0 {
+		opts = append(opts, otlpmetrichttp.WithHeaders(c.Headers))
+	}
+	return opts
+}
+
+func (c Config) otlpGRPCOptions() []otlpmetricgrpc.Option {
+	var opts []otlpmetricgrpc.Option
+	if c.Endpoint != "" {
+		if strings.Contains(c.Endpoint, "://") {
+			opts = append(opts, otlpmetricgrpc.WithEndpointURL(c.Endpoint))
+		} else {
+			opts = append(opts, otlpmetricgrpc.WithEndpoint(c.Endpoint))
+		}
+	}
+	if c.Insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+	if len(c.Headers) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(c.Headers))
+	}
+	return opts
+}
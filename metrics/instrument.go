@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Units following the UCUM conventions of OpenTelemetry.
+// Counts of things use an annotation instead, see CountUnit.
+const (
+	UnitSeconds       = "s"
+	UnitMilliseconds  = "ms"
+	UnitBytes         = "By"
+	UnitDimensionless = "1" // ratios and utilization
+)
+
+// DurationBuckets are the histogram boundaries, in seconds, used for
+// instruments with UnitSeconds when no buckets are given.
+var DurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10}
+
+// namePattern matches lowercase, dot-separated names such as "http.server.request.duration".
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)*$`)
+
+// unitSuffixes are added by the Prometheus exporter and must not be part of names.
+var unitSuffixes = []string{"_total", "_seconds", "_milliseconds", "_bytes", "_ratio"}
+
+// CountUnit returns the unit annotation for a count of noun, e.g. "{request}".
+func CountUnit(noun string) string {
+	return "{" + noun + "}"
+}
+
+// validateInstrument checks the naming conventions of an instrument.
+func validateInstrument(name, unit string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid metric name %q: use lowercase dot-separated words", name)
+	}
+	for _, suffix := range unitSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return fmt.Errorf("invalid metric name %q: set the unit instead of the %s suffix", name, suffix)
+		}
+	}
+	if unit == "" {
+		return fmt.Errorf("unit is required for metric %q", name)
+	}
+	return nil
+}
+
+// Counter creates a monotonic integer counter, e.g. handled requests.
+func Counter(name, description, unit string) (metric.Int64Counter, error) {
+	if err := validateInstrument(name, unit); err != nil {
+		return nil, err
+	}
+	return Meter().Int64Counter(name, metric.WithDescription(description), metric.WithUnit(unit))
+}
+
+// UpDownCounter creates an integer counter that can decrease, e.g. active connections.
+func UpDownCounter(name, description, unit string) (metric.Int64UpDownCounter, error) {
+	if err := validateInstrument(name, unit); err != nil {
+		return nil, err
+	}
+	return Meter().Int64UpDownCounter(name, metric.WithDescription(description), metric.WithUnit(unit))
+}
+
+// Histogram creates a histogram with the given bucket boundaries.
+// Without buckets, DurationBuckets are used for UnitSeconds and the SDK defaults otherwise.
+func Histogram(name, description, unit string, buckets ...float64) (metric.Float64Histogram, error) {
+	if err := validateInstrument(name, unit); err != nil {
+		return nil, err
+	}
+	if len(buckets) == 0 && unit == UnitSeconds {
+		buckets = DurationBuckets
+	}
+	opts := []metric.Float64HistogramOption{metric.WithDescription(description), metric.WithUnit(unit)}
+	if len(buckets) > 0 {
+		opts = append(opts, metric.WithExplicitBucketBoundaries(buckets...))
+	}
+	return Meter().Float64Histogram(name, opts...)
+}
+
+// Gauge creates a gauge recording the current value, e.g. queue depth.
+func Gauge(name, description, unit string) (metric.Float64Gauge, error) {
+	if err := validateInstrument(name, unit); err != nil {
+		return nil, err
+	}
+	return Meter().Float64Gauge(name, metric.WithDescription(description), metric.WithUnit(unit))
+}
+
+// ObservableGauge creates a gauge whose value is observed on each collection.
+func ObservableGauge(name, description, unit string, callback metric.Float64Callback) (metric.Float64ObservableGauge, error) {
+	if err := validateInstrument(name, unit); err != nil {
+		return nil, err
+	}
+	return Meter().Float64ObservableGauge(name,
+		metric.WithDescription(description),
+		metric.WithUnit(unit),
+		metric.WithFloat64Callback(callback),
+	)
+}
+
+// Must panics if err is not nil. It is meant for package-level instruments:
+//
+//	var requests = metrics.Must(metrics.Counter("orders.created", "Created orders", metrics.CountUnit("order")))
+func Must[T any](instrument T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return instrument
+}
+
+// RecordDuration records the seconds elapsed since start.
+// Usage:
+//
+//	defer metrics.RecordDuration(ctx, latency, time.Now(), attribute.String("route", route))
+func RecordDuration(ctx context.Context, h metric.Float64Histogram, start time.Time, attrs ...attribute.KeyValue) {
+	h.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"go.opentelemetry.io/contrib/instrumentation/runtime"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// instrumentationName identifies the meter used by the instrument helpers.
+const instrumentationName = "github.com/laziness-coders/go-utils/metrics"
+
+// globalProvider is the provider installed by the last Init.
+var globalProvider atomic.Pointer[Provider]
+
+// Provider is a MeterProvider with the scrape handler of its exporter.
+type Provider struct {
+	*sdkmetric.MeterProvider
+	handler http.Handler
+}
+
+// Handler serves the metrics in the Prometheus text format.
+// It responds 404 unless the provider uses ExporterPrometheus.
+func (p *Provider) Handler() http.Handler {
+	if p.handler == nil {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "metrics are not exported with prometheus", http.StatusNotFound)
+		})
+	}
+	return p.handler
+}
+
+// Init builds a Provider from cfg and installs it as the global OpenTelemetry
+// MeterProvider. The returned func flushes pending metrics and stops the
+// provider; call it on application shutdown.
+func Init(ctx context.Context, cfg Config) (shutdown func(context.Context) error, err error) {
+	p, err := NewMeterProvider(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	otel.SetMeterProvider(p)
+	globalProvider.Store(p)
+	return p.Shutdown, nil
+}
+
+// NewMeterProvider builds a Provider from cfg without installing it globally.
+// Runtime metrics are recorded unless cfg.DisableRuntimeMetrics is set.
+func NewMeterProvider(ctx context.Context, cfg Config) (*Provider, error) {
+	cfg = cfg.setDefaults()
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	res, err := cfg.buildResource(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build resource: %w", err)
+	}
+
+	reader, handler, err := cfg.buildReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s exporter: %w", cfg.Exporter, err)
+	}
+
+	opts := []sdkmetric.Option{sdkmetric.WithResource(res)}
+	if reader != nil {
+		opts = append(opts, sdkmetric.WithReader(reader))
+	}
+	p := &Provider{MeterProvider: sdkmetric.NewMeterProvider(opts...), handler: handler}
+
+	if !cfg.DisableRuntimeMetrics {
+		if err := runtime.Start(runtime.WithMeterProvider(p)); err != nil {
+			_ = p.Shutdown(ctx)
+			return nil, fmt.Errorf("failed to start runtime metrics: %w", err)
+		}
+	}
+	return p, nil
+}
+
+func (c Config) buildResource(ctx context.Context) (*resource.Resource, error) {
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName(c.ServiceName),
+		semconv.DeploymentEnvironmentName(string(c.Environment)),
+	}
+	if c.ServiceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersion(c.ServiceVersion))
+	}
+	return resource.New(ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithAttributes(attrs...),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+}
+
+// Handler serves the metrics of the provider installed by Init in the
+// Prometheus text format. It can be mounted before Init, e.g. on /metrics.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := globalProvider.Load()
+		if p == nil {
+			http.Error(w, "metrics are not initialized", http.StatusServiceUnavailable)
+			return
+		}
+		p.Handler().ServeHTTP(w, r)
+	})
+}
+
+// Meter returns the meter of this package from the global provider.
+func Meter() metric.Meter {
+	return otel.Meter(instrumentationName)
+}
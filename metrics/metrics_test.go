@@ -0,0 +1,209 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/laziness-coders/go-utils/configs"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func initTestReader(t *testing.T, cfg Config) *TestReader {
+	t.Helper()
+	reader := NewTestReader()
+	cfg.Reader = reader
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = "orders"
+	}
+	shutdown, err := Init(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	t.Cleanup(func() { _ = shutdown(context.Background()) })
+	return reader
+}
+
+func TestInstruments(t *testing.T) {
+	reader := initTestReader(t, Config{DisableRuntimeMetrics: true})
+	ctx := context.Background()
+	ok := attribute.String("status", "ok")
+
+	created := Must(Counter("orders.created", "Created orders", CountUnit("order")))
+	created.Add(ctx, 2, metric.WithAttributes(ok))
+	created.Add(ctx, 1, metric.WithAttributes(ok))
+	created.Add(ctx, 1, metric.WithAttributes(attribute.String("status", "failed")))
+
+	active := Must(UpDownCounter("orders.active", "Orders being processed", CountUnit("order")))
+	active.Add(ctx, 3)
+	active.Add(ctx, -1)
+
+	depth := Must(Gauge("orders.queue.depth", "Queued orders", CountUnit("order")))
+	depth.Record(ctx, 7)
+	depth.Record(ctx, 5)
+
+	_ = Must(ObservableGauge("orders.cache.ratio", "Cache hit ratio", UnitDimensionless,
+		func(_ context.Context, o metric.Float64Observer) error {
+			o.Observe(0.75)
+			return nil
+		}))
+
+	latency := Must(Histogram("orders.duration", "Order processing time", UnitSeconds))
+	latency.Record(ctx, 0.2)
+	RecordDuration(ctx, latency, time.Now().Add(-100*time.Millisecond))
+
+	tests := []struct {
+		name  string
+		attrs []attribute.KeyValue
+		want  float64
+	}{
+		{name: "orders.created", attrs: []attribute.KeyValue{ok}, want: 3},
+		{name: "orders.created", attrs: []attribute.KeyValue{attribute.String("status", "failed")}, want: 1},
+		{name: "orders.active", want: 2},
+		{name: "orders.queue.depth", want: 5},
+		{name: "orders.cache.ratio", want: 0.75},
+	}
+	for _, tt := range tests {
+		got, found := reader.Value(tt.name, tt.attrs...)
+		if !found || got != tt.want {
+			t.Errorf("Value(%s, %v) = %v, %v, want %v", tt.name, tt.attrs, got, found, tt.want)
+		}
+	}
+
+	if _, found := reader.Value("orders.created"); found {
+		t.Error("Value() should only match the exact attribute set")
+	}
+	if n, found := reader.Count("orders.duration"); !found || n != 2 {
+		t.Errorf("Count(orders.duration) = %d, %v, want 2", n, found)
+	}
+	if sum, _ := reader.Value("orders.duration"); sum < 0.3 || sum > 1 {
+		t.Errorf("Value(orders.duration) = %v, want about 0.3", sum)
+	}
+}
+
+func TestRuntimeMetrics(t *testing.T) {
+	tests := []struct {
+		name    string
+		disable bool
+		want    bool
+	}{
+		{name: "on by default", disable: false, want: true},
+		{name: "disabled", disable: true, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := initTestReader(t, Config{DisableRuntimeMetrics: tt.disable})
+			_, found := reader.Value("go.goroutine.count")
+			if found != tt.want {
+				t.Errorf("go.goroutine.count recorded = %v, want %v", found, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_Prometheus(t *testing.T) {
+	shutdown, err := Init(context.Background(), Config{
+		ServiceName: "orders",
+		Environment: configs.AppEnvironmentTest,
+	})
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	defer func() { _ = shutdown(context.Background()) }()
+
+	created := Must(Counter("orders.created", "Created orders", CountUnit("order")))
+	created.Add(context.Background(), 4)
+	latency := Must(Histogram("orders.duration", "Order processing time", UnitSeconds))
+	latency.Record(context.Background(), 0.02)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	for _, want := range []string{
+		"# HELP orders_created_total Created orders",
+		"orders_created_total{",
+		`orders_duration_seconds_bucket{`,
+		`le="0.025"`,
+		"go_goroutine_count",
+		`service_name="orders"`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("scrape output misses %q", want)
+		}
+	}
+}
+
+func TestHandler_NotPrometheus(t *testing.T) {
+	initTestReader(t, Config{DisableRuntimeMetrics: true})
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestNewMeterProvider_Config(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "prometheus by default", cfg: Config{ServiceName: "svc"}, wantErr: false},
+		{name: "otlp http", cfg: Config{ServiceName: "svc", Exporter: ExporterOTLPHTTP, Endpoint: "localhost:4318", Insecure: true}, wantErr: false},
+		{name: "otlp grpc url", cfg: Config{ServiceName: "svc", Exporter: ExporterOTLPGRPC, Endpoint: "http://localhost:4317"}, wantErr: false},
+		{name: "none", cfg: Config{ServiceName: "svc", Exporter: ExporterNone}, wantErr: false},
+		{name: "missing service name", cfg: Config{}, wantErr: true},
+		{name: "invalid exporter", cfg: Config{ServiceName: "svc", Exporter: "statsd"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.DisableRuntimeMetrics = true
+			p, err := NewMeterProvider(context.Background(), tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewMeterProvider() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if p != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+				defer cancel()
+				_ = p.Shutdown(ctx)
+			}
+		})
+	}
+}
+
+func TestValidateInstrument(t *testing.T) {
+	tests := []struct {
+		name    string
+		unit    string
+		wantErr bool
+	}{
+		{name: "http.server.request.duration", unit: UnitSeconds, wantErr: false},
+		{name: "db.client.connections_idle", unit: CountUnit("connection"), wantErr: false},
+		{name: "HTTP.Requests", unit: CountUnit("request"), wantErr: true},
+		{name: "http-requests", unit: CountUnit("request"), wantErr: true},
+		{name: "http.requests_total", unit: CountUnit("request"), wantErr: true},
+		{name: "http.duration_seconds", unit: UnitSeconds, wantErr: true},
+		{name: "http.requests", unit: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateInstrument(tt.name, tt.unit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateInstrument() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// TestReader collects metrics on demand, to assert on recorded values offline.
+// Usage:
+//
+//	reader := metrics.NewTestReader()
+//	shutdown, _ := metrics.Init(ctx, metrics.Config{ServiceName: "test", Reader: reader})
+//	...
+//	n, ok := reader.Value("orders.created", attribute.String("status", "ok"))
+type TestReader struct {
+	*sdkmetric.ManualReader
+}
+
+// NewTestReader creates a TestReader to be set as Config.Reader.
+func NewTestReader() *TestReader {
+	return &TestReader{ManualReader: sdkmetric.NewManualReader()}
+}
+
+// Metrics collects all metrics, across instrumentation scopes.
+func (r *TestReader) Metrics() ([]metricdata.Metrics, error) {
+	var rm metricdata.ResourceMetrics
+	if err := r.Collect(context.Background(), &rm); err != nil {
+		return nil, err
+	}
+	var all []metricdata.Metrics
+	for _, sm := range rm.ScopeMetrics {
+		all = append(all, sm.Metrics...)
+	}
+	return all, nil
+}
+
+// Value returns the value of the named metric for exactly the given attributes:
+// the sum of counters, the last value of gauges and the sum of histograms.
+func (r *TestReader) Value(name string, attrs ...attribute.KeyValue) (float64, bool) {
+	set := attribute.NewSet(attrs...)
+	m, ok := r.find(name)
+	if !ok {
+		return 0, false
+	}
+
+	switch data := m.Data.(type) {
+	case metricdata.Sum[int64]:
+		return pointValue(data.DataPoints, set)
+	case metricdata.Sum[float64]:
+		return pointValue(data.DataPoints, set)
+	case metricdata.Gauge[int64]:
+		return pointValue(data.DataPoints, set)
+	case metricdata.Gauge[float64]:
+		return pointValue(data.DataPoints, set)
+	case metricdata.Histogram[float64]:
+		for _, dp := range data.DataPoints {
+			if dp.Attributes.Equals(&set) {
+				return dp.Sum, true
+			}
+		}
+	case metricdata.Histogram[int64]:
+		for _, dp := range data.DataPoints {
+			if dp.Attributes.Equals(&set) {
+				return float64(dp.Sum), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// Count returns the number of measurements recorded by the named histogram
+// for exactly the given attributes.
+func (r *TestReader) Count(name string, attrs ...attribute.KeyValue) (uint64, bool) {
+	set := attribute.NewSet(attrs...)
+	m, ok := r.find(name)
+	if !ok {
+		return 0, false
+	}
+
+	switch data := m.Data.(type) {
+	case metricdata.Histogram[float64]:
+		for _, dp := range data.DataPoints {
+			if dp.Attributes.Equals(&set) {
+				return dp.Count, true
+			}
+		}
+	case metricdata.Histogram[int64]:
+		for _, dp := range data.DataPoints {
+			if dp.Attributes.Equals(&set) {
+				return dp.Count, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func (r *TestReader) find(name string) (metricdata.Metrics, bool) {
+	all, err := r.Metrics()
+	if err != nil {
+		return metricdata.Metrics{}, false
+	}
+	for _, m := range all {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return metricdata.Metrics{}, false
+}
+
+func pointValue[N int64 | float64](points []metricdata.DataPoint[N], set attribute.Set) (float64, bool) {
+	for _, dp := range points {
+		if dp.Attributes.Equals(&set) {
+			return float64(dp.Value), true
+		}
+	}
+	return 0, false
+}
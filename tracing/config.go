@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"fmt"
+
+	"github.com/laziness-coders/go-utils/configs"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Exporter constants
+const (
+	ExporterStdout   = "stdout"   // spans as JSON on stdout
+	ExporterOTLPHTTP = "otlphttp" // OTLP over HTTP/protobuf
+	ExporterOTLPGRPC = "otlpgrpc" // OTLP over gRPC
+	ExporterMemory   = "memory"   // in-memory, for tests (see Spans)
+	ExporterNone     = "none"     // spans are created but not exported
+)
+
+const defaultSampleRatio = 1.0
+
+// Config defines the tracing configuration.
+// It can be decoded by configs.ConfigLoader, e.g. as a TRACING section.
+type Config struct {
+	ServiceName    string                 `mapstructure:"SERVICE_NAME"`    // required
+	ServiceVersion string                 `mapstructure:"SERVICE_VERSION"` // optional
+	Environment    configs.AppEnvironment `mapstructure:"ENVIRONMENT"`     // default: APP_ENV, then dev
+	SampleRatio    float64                `mapstructure:"SAMPLE_RATIO"`    // fraction of root traces to sample (default: 1)
+	Exporter       string                 `mapstructure:"EXPORTER"`        // stdout, otlphttp, otlpgrpc, memory, none (default: stdout)
+	Endpoint       string                 `mapstructure:"ENDPOINT"`        // OTLP host:port or URL (default: OTEL_EXPORTER_OTLP_* env)
+	Insecure       bool                   `mapstructure:"INSECURE"`        // disable TLS for OTLP
+	Headers        map[string]string      `mapstructure:"HEADERS"`         // extra OTLP headers, e.g. API keys
+
+	// SpanExporter overrides Exporter with a custom exporter.
+	SpanExporter sdktrace.SpanExporter `mapstructure:"-"`
+}
+
+// ConfigFromApp returns a Config named and scoped after the application config.
+func ConfigFromApp(app configs.AppConfig) Config {
+	return Config{
+		ServiceName: app.AppName,
+		Environment: app.AppEnv,
+	}
+}
+
+// setDefaults applies default values to the configuration.
+func (c Config) setDefaults() Config {
+	if c.Environment == "" {
+		c.Environment = configs.GetEnv("APP_ENV", string(configs.AppEnvironmentDev))
+	}
+	if c.SampleRatio <= 0 {
+		c.SampleRatio = defaultSampleRatio
+	}
+	if c.Exporter == "" {
+		c.Exporter = ExporterStdout
+	}
+	return c
+}
+
+// validate checks if the configuration is valid.
+func (c Config) validate() error {
+	if c.ServiceName == "" {
+		return fmt.Errorf("service name is required")
+	}
+	if c.SampleRatio > 1 {
+		return fmt.Errorf("invalid sample ratio: %v", c.SampleRatio)
+	}
+	if c.SpanExporter != nil {
+		return nil
+	}
+	switch c.Exporter {
+	case ExporterStdout, ExporterOTLPHTTP, ExporterOTLPGRPC, ExporterMemory, ExporterNone:
+		// valid
+	default:
+		return fmt.Errorf("invalid exporter: %s", c.Exporter)
+	}
+	return nil
+}
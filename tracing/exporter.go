@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// buildExporter creates the span exporter selected by the configuration.
+// It returns nil for ExporterNone.
+func (c Config) buildExporter(ctx context.Context) (sdktrace.SpanExporter, error) {
+	if c.SpanExporter != nil {
+		return c.SpanExporter, nil
+	}
+
+	switch c.Exporter {
+	case ExporterStdout:
+		return stdouttrace.New()
+	case ExporterOTLPHTTP:
+		return otlptracehttp.New(ctx, c.otlpHTTPOptions()...)
+	case ExporterOTLPGRPC:
+		return otlptracegrpc.New(ctx, c.otlpGRPCOptions()...)
+	case ExporterMemory:
+		return newMemoryExporter(), nil
+	case ExporterNone:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("invalid exporter: %s", c.Exporter)
+	}
+}
+
+func (c Config) otlpHTTPOptions() []otlptracehttp.Option {
+	var opts []otlptracehttp.Option
+	if c.Endpoint != "" {
+		if strings.Contains(c.Endpoint, "://") {
+			opts = append(opts, otlptracehttp.WithEndpointURL(c.Endpoint))
+		} else {
+			opts = append(opts, otlptracehttp.WithEndpoint(c.Endpoint))
+		}
+	}
+	if c.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if len(c.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(c.Headers))
+	}
+	return opts
+}
+
+func (c Config) otlpGRPCOptions() []otlptracegrpc.Option {
+	var opts []otlptracegrpc.Option
+	if c.Endpoint != "" {
+		if strings.Contains(c.Endpoint, "://") {
+			opts = append(opts, otlptracegrpc.WithEndpointURL(c.Endpoint))
+		} else {
+			opts = append(opts, otlptracegrpc.WithEndpoint(c.Endpoint))
+		}
+	}
+	if c.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	if len(c.Headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(c.Headers))
+	}
+	return opts
+}
@@ -0,0 +1,172 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	utilerrors "github.com/laziness-coders/go-utils/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the tracer used by Start.
+const instrumentationName = "github.com/laziness-coders/go-utils/tracing"
+
+// memoryExporter holds the exporter of the last Init with ExporterMemory.
+var memoryExporter atomic.Pointer[tracetest.InMemoryExporter]
+
+// Init builds a TracerProvider from cfg and installs it, together with the
+// W3C trace context and baggage propagators, as the global OpenTelemetry
+// provider. The returned func flushes pending spans and stops the provider;
+// call it on application shutdown.
+func Init(ctx context.Context, cfg Config) (shutdown func(context.Context) error, err error) {
+	tp, err := NewTracerProvider(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	return tp.Shutdown, nil
+}
+
+// NewTracerProvider builds a TracerProvider from cfg without installing it globally.
+func NewTracerProvider(ctx context.Context, cfg Config) (*sdktrace.TracerProvider, error) {
+	cfg = cfg.setDefaults()
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	res, err := cfg.buildResource(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build resource: %w", err)
+	}
+
+	exporter, err := cfg.buildExporter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s exporter: %w", cfg.Exporter, err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	}
+	switch {
+	case exporter == nil:
+		// ExporterNone: spans still carry IDs for propagation and log correlation.
+	case cfg.Exporter == ExporterMemory && cfg.SpanExporter == nil:
+		// Export synchronously so tests see spans as soon as they end.
+		opts = append(opts, sdktrace.WithSyncer(exporter))
+	default:
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	return sdktrace.NewTracerProvider(opts...), nil
+}
+
+func (c Config) buildResource(ctx context.Context) (*resource.Resource, error) {
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName(c.ServiceName),
+		semconv.DeploymentEnvironmentName(string(c.Environment)),
+	}
+	if c.ServiceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersion(c.ServiceVersion))
+	}
+	return resource.New(ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithAttributes(attrs...),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+}
+
+func newMemoryExporter() *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	memoryExporter.Store(exporter)
+	return exporter
+}
+
+// Spans returns the spans recorded since Init with ExporterMemory, or nil.
+func Spans() tracetest.SpanStubs {
+	if exporter := memoryExporter.Load(); exporter != nil {
+		return exporter.GetSpans()
+	}
+	return nil
+}
+
+// ResetSpans clears the spans recorded with ExporterMemory.
+func ResetSpans() {
+	if exporter := memoryExporter.Load(); exporter != nil {
+		exporter.Reset()
+	}
+}
+
+// Tracer returns the tracer of this package from the global provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts a span with the global provider.
+// Usage:
+//
+//	ctx, span := tracing.Start(ctx, "orders.Create")
+//	defer tracing.End(span, &err)
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// End records *errp on the span, if any, and ends it.
+func End(span trace.Span, errp *error) {
+	if errp != nil {
+		RecordError(span, *errp)
+	}
+	span.End()
+}
+
+// RecordError records err as an exception event and marks the span as failed.
+// For errors created by the errors package, the file and line where the error
+// was created are added to the event.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	var attrs []attribute.KeyValue
+	var e *utilerrors.Error
+	if errors.As(err, &e) {
+		attrs = append(attrs, frameAttributes(e.Frame())...)
+	}
+
+	span.RecordError(err, trace.WithAttributes(attrs...))
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// frameAttributes converts a "/path/to/file.go:123" frame into code attributes.
+func frameAttributes(frame string) []attribute.KeyValue {
+	i := strings.LastIndexByte(frame, ':')
+	if i < 0 {
+		return nil
+	}
+	line, err := strconv.Atoi(frame[i+1:])
+	if err != nil {
+		return nil
+	}
+	return []attribute.KeyValue{
+		semconv.CodeFilePath(frame[:i]),
+		semconv.CodeLineNumber(line),
+	}
+}
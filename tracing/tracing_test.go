@@ -0,0 +1,188 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/laziness-coders/go-utils/configs"
+	utilerrors "github.com/laziness-coders/go-utils/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+func initMemory(t *testing.T, cfg Config) {
+	t.Helper()
+	cfg.Exporter = ExporterMemory
+	shutdown, err := Init(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	t.Cleanup(func() { _ = shutdown(context.Background()) })
+}
+
+func TestInit_Memory(t *testing.T) {
+	initMemory(t, Config{
+		ServiceName:    "orders",
+		ServiceVersion: "1.2.3",
+		Environment:    configs.AppEnvironmentTest,
+	})
+
+	ctx, parent := Start(context.Background(), "parent")
+	_, child := Start(ctx, "child")
+	child.End()
+	parent.End()
+
+	spans := Spans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	if spans[0].Name != "child" || spans[0].Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("child span not linked to its parent: %+v", spans[0])
+	}
+
+	attrs := spans[1].Resource.Set()
+	for key, want := range map[string]string{
+		string(semconv.ServiceNameKey):               "orders",
+		string(semconv.ServiceVersionKey):            "1.2.3",
+		string(semconv.DeploymentEnvironmentNameKey): "test",
+	} {
+		got, ok := attrs.Value(attribute.Key(key))
+		if !ok || got.AsString() != want {
+			t.Errorf("resource %s = %v, want %s", key, got.AsString(), want)
+		}
+	}
+
+	ResetSpans()
+	if len(Spans()) != 0 {
+		t.Error("ResetSpans() should clear the recorded spans")
+	}
+}
+
+func TestInit_Propagators(t *testing.T) {
+	initMemory(t, Config{ServiceName: "orders"})
+
+	ctx, span := Start(context.Background(), "outgoing")
+	defer span.End()
+
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	want := fmt.Sprintf("00-%s-%s-01", span.SpanContext().TraceID(), span.SpanContext().SpanID())
+	if carrier["traceparent"] != want {
+		t.Errorf("traceparent = %q, want %q", carrier["traceparent"], want)
+	}
+}
+
+func TestEnd_RecordsErrors(t *testing.T) {
+	initMemory(t, Config{ServiceName: "orders"})
+
+	run := func(err error) error {
+		_, span := Start(context.Background(), "operation")
+		defer End(span, &err)
+		return err
+	}
+
+	_ = run(nil)
+	_ = run(fmt.Errorf("plain error"))
+	_ = run(utilerrors.Wrap(fmt.Errorf("db down"), "create order"))
+
+	spans := Spans()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+
+	if spans[0].Status.Code != codes.Unset || len(spans[0].Events) != 0 {
+		t.Errorf("successful span should not record errors: %+v", spans[0])
+	}
+	if spans[1].Status.Code != codes.Error || spans[1].Status.Description != "plain error" {
+		t.Errorf("unexpected status: %+v", spans[1].Status)
+	}
+
+	event := findEvent(t, spans[2], "exception")
+	var file string
+	var line int64
+	for _, attr := range event.Attributes {
+		switch attr.Key {
+		case semconv.CodeFilePathKey:
+			file = attr.Value.AsString()
+		case semconv.CodeLineNumberKey:
+			line = attr.Value.AsInt64()
+		}
+	}
+	if !strings.HasSuffix(file, "tracing_test.go") || line == 0 {
+		t.Errorf("exception event should carry the error frame, got %s:%d", file, line)
+	}
+}
+
+func TestNewTracerProvider_Sampling(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp, err := NewTracerProvider(context.Background(), Config{
+		ServiceName:  "orders",
+		SampleRatio:  0.000001,
+		SpanExporter: exporter,
+	})
+	if err != nil {
+		t.Fatalf("NewTracerProvider() error = %v", err)
+	}
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	for i := 0; i < 10; i++ {
+		_, span := tp.Tracer("test").Start(context.Background(), "sampled-out")
+		if span.SpanContext().IsSampled() {
+			t.Fatal("span should not be sampled")
+		}
+		span.End()
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "stdout by default", cfg: Config{ServiceName: "svc"}, wantErr: false},
+		{name: "otlp http", cfg: Config{ServiceName: "svc", Exporter: ExporterOTLPHTTP, Endpoint: "localhost:4318", Insecure: true}, wantErr: false},
+		{name: "otlp grpc url", cfg: Config{ServiceName: "svc", Exporter: ExporterOTLPGRPC, Endpoint: "http://localhost:4317"}, wantErr: false},
+		{name: "none", cfg: Config{ServiceName: "svc", Exporter: ExporterNone}, wantErr: false},
+		{name: "missing service name", cfg: Config{}, wantErr: true},
+		{name: "invalid exporter", cfg: Config{ServiceName: "svc", Exporter: "zipkin"}, wantErr: true},
+		{name: "invalid ratio", cfg: Config{ServiceName: "svc", SampleRatio: 2}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp, err := NewTracerProvider(context.Background(), tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewTracerProvider() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tp != nil {
+				_ = tp.Shutdown(context.Background())
+			}
+		})
+	}
+}
+
+func TestConfigFromApp(t *testing.T) {
+	cfg := ConfigFromApp(configs.AppConfig{AppName: "orders", AppEnv: configs.AppEnvironmentProd})
+	if cfg.ServiceName != "orders" || cfg.Environment != configs.AppEnvironmentProd {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+}
+
+func findEvent(t *testing.T, span tracetest.SpanStub, name string) sdktrace.Event {
+	t.Helper()
+	for _, e := range span.Events {
+		if e.Name == name {
+			return e
+		}
+	}
+	t.Fatalf("span %s has no %s event", span.Name, name)
+	return sdktrace.Event{}
+}